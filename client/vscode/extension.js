@@ -1,3 +1,4 @@
+const vscode = require("vscode");
 const { LanguageClient, TransportKind } = require("vscode-languageclient/node");
 
 module.exports = {
@@ -18,6 +19,8 @@ module.exports = {
         /** @type {import("vscode-languageclient/node").LanguageClientOptions} */
         const clientOptions = {
             documentSelector: [{ scheme: "file", language: "yaml" }],
+            // client commands the server may send, registered below
+            initializationOptions: { commands: ["tekton.showReferences"] },
         };
 
         const client = new LanguageClient(
@@ -27,6 +30,18 @@ module.exports = {
             clientOptions
         );
 
+        // the server sends protocol values, which editor.action.showReferences
+        // doesn't accept
+        context.subscriptions.push(vscode.commands.registerCommand(
+            "tekton.showReferences",
+            (uri, position, locations) => vscode.commands.executeCommand(
+                "editor.action.showReferences",
+                vscode.Uri.parse(uri),
+                client.protocol2CodeConverter.asPosition(position),
+                locations.map((l) => client.protocol2CodeConverter.asLocation(l)),
+            ),
+        ));
+
         client.start();
     },
 };
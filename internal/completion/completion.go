@@ -2,19 +2,215 @@ package completion
 
 import (
 	"fmt"
-	"strings"
+	"math"
+	"sort"
 )
 
+const (
+	// scoreMatch is awarded for each query character found in a candidate.
+	scoreMatch = 16
+
+	// bonusConsecutive is awarded for each matched character immediately
+	// following the previous match. It's higher than bonusBoundary so that
+	// contiguous runs beat matches scattered across words.
+	bonusConsecutive = 14
+
+	// bonusBoundary is awarded for matches at the start of a word, that is
+	// at the start of the candidate or after a separator such as `-` or `.`.
+	bonusBoundary = 12
+
+	// bonusCamel is awarded for an uppercase match following a lowercase
+	// character, as in the `R` of `taskRun`.
+	bonusCamel = 10
+
+	// bonusCase is awarded for matches of the same case as the query.
+	bonusCase = 1
+
+	// penaltyGap is subtracted for each character skipped between matches.
+	penaltyGap = 1
+
+	// penaltyLeading is subtracted for each character skipped before the
+	// first match, up to maxLeadingPenalty.
+	penaltyLeading    = 1
+	maxLeadingPenalty = 8
+)
+
+// noMatch is the score of positions which can't be matched.
+const noMatch = math.MinInt32
+
+// matcher scores candidates against a query, reusing its buffers between
+// candidates.
+type matcher struct {
+	query []byte
+	lower []byte
+
+	prev []int
+	cur  []int
+}
+
+func newMatcher(query string) *matcher {
+	return &matcher{
+		query: []byte(query),
+		lower: toLower([]byte(query)),
+	}
+}
+
+// Score returns the score of a candidate for the given query, and false if
+// the query isn't a subsequence of the candidate, ignoring case. Higher
+// scores are better matches.
+func Score(query, candidate string) (int, bool) {
+	return newMatcher(query).score(candidate)
+}
+
+func (m *matcher) score(candidate string) (int, bool) {
+	n, c := len(m.query), len(candidate)
+	if n == 0 {
+		return 0, true
+	}
+	if n > c || !m.isSubsequence(candidate) {
+		return 0, false
+	}
+
+	if cap(m.prev) < c {
+		m.prev = make([]int, c)
+		m.cur = make([]int, c)
+	}
+	prev, cur := m.prev[:c], m.cur[:c]
+
+	// cur[j] holds the best score of matching query[:i+1] with query[i]
+	// matched at candidate[j].
+	for i := 0; i < n; i++ {
+		running := noMatch
+		for j := 0; j < c; j++ {
+			if i > 0 && j >= 2 {
+				// best match of query[i-1] before candidate[j-1],
+				// penalized by the gap up to j. noMatch is never
+				// penalized, which would overflow 32-bit ints.
+				if best := max(running, prev[j-2]); best != noMatch {
+					running = best - penaltyGap
+				}
+			}
+
+			cur[j] = noMatch
+			ch := candidate[j]
+			if lowerByte(ch) != m.lower[i] {
+				continue
+			}
+			s := scoreMatch + bonusAt(candidate, j)
+			if ch == m.query[i] {
+				s += bonusCase
+			}
+
+			if i == 0 {
+				cur[j] = s - min(j, maxLeadingPenalty)*penaltyLeading
+				continue
+			}
+			best := running
+			if j > 0 && prev[j-1] != noMatch {
+				best = max(best, prev[j-1]+bonusConsecutive)
+			}
+			if best == noMatch {
+				continue
+			}
+			cur[j] = best + s
+		}
+		prev, cur = cur, prev
+	}
+
+	best := noMatch
+	for _, s := range prev {
+		best = max(best, s)
+	}
+	return best, best != noMatch
+}
+
+// isSubsequence returns true if the lowercase query is a subsequence of the
+// candidate, ignoring case.
+func (m *matcher) isSubsequence(candidate string) bool {
+	i := 0
+	for j := 0; j < len(candidate) && i < len(m.lower); j++ {
+		if lowerByte(candidate[j]) == m.lower[i] {
+			i++
+		}
+	}
+	return i == len(m.lower)
+}
+
+// bonusAt returns the bonus of matching the character at position j of the
+// candidate.
+func bonusAt(candidate string, j int) int {
+	if j == 0 {
+		return bonusBoundary
+	}
+	prev, ch := candidate[j-1], candidate[j]
+	switch prev {
+	case '-', '.', '_', '(', '$', '[', '"', '\'', ' ', '/':
+		return bonusBoundary
+	}
+	if isLower(prev) && isUpper(ch) {
+		return bonusCamel
+	}
+	return 0
+}
+
+func isLower(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
+
+func isUpper(b byte) bool {
+	return b >= 'A' && b <= 'Z'
+}
+
+func lowerByte(b byte) byte {
+	if isUpper(b) {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+func toLower(bs []byte) []byte {
+	for i, b := range bs {
+		bs[i] = lowerByte(b)
+	}
+	return bs
+}
+
 // Solve filters relevant completion suggestion given a query. A query is
 // usually the word partially typed before a completion has been requested.
+// Candidates match if the query is a subsequence of them, ignoring case, and
+// are returned ranked by score, then by length. Candidates are returned in
+// their original order for an empty query.
 func Solve(query string, candidates []fmt.Stringer) []fmt.Stringer {
-	rs := []fmt.Stringer{}
+	if query == "" {
+		return candidates
+	}
+
+	type match struct {
+		c     fmt.Stringer
+		score int
+		size  int
+	}
+
+	m := newMatcher(query)
+	ms := make([]match, 0, len(candidates))
 	for _, c := range candidates {
-		// TODO: match subsequence instead of a prefix (fuzzy finder)
-		if !strings.HasPrefix(c.String(), query) {
+		text := c.String()
+		s, ok := m.score(text)
+		if !ok {
 			continue
 		}
-		rs = append(rs, c)
+		ms = append(ms, match{c, s, len(text)})
+	}
+	sort.SliceStable(ms, func(i, j int) bool {
+		if ms[i].score != ms[j].score {
+			return ms[i].score > ms[j].score
+		}
+		return ms[i].size < ms[j].size
+	})
+
+	rs := make([]fmt.Stringer, len(ms))
+	for i, m := range ms {
+		rs[i] = m.c
 	}
 	return rs
 }
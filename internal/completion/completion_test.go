@@ -0,0 +1,184 @@
+package completion
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type text string
+
+func (t text) String() string {
+	return string(t)
+}
+
+func texts(ss ...string) []fmt.Stringer {
+	rs := make([]fmt.Stringer, len(ss))
+	for i, s := range ss {
+		rs[i] = text(s)
+	}
+	return rs
+}
+
+func TestScore(t *testing.T) {
+	tcs := []struct {
+		query     string
+		candidate string
+		match     bool
+	}{
+		{query: "", candidate: "$(params.foo)", match: true},
+		{query: "$(params", candidate: "$(params.foo)", match: true},
+		{query: "foo", candidate: "$(params.foo)", match: true},
+		{query: "pfoo", candidate: "$(params.foo)", match: true},
+		{query: "FOO", candidate: "$(params.foo)", match: true},
+		{query: "trn", candidate: "$(context.taskRun.name)", match: true},
+		{query: "oof", candidate: "$(params.foo)", match: false},
+		{query: "$(params.foo)x", candidate: "$(params.foo)", match: false},
+		{query: "z", candidate: "", match: false},
+	}
+	for _, tc := range tcs {
+		if _, ok := Score(tc.query, tc.candidate); ok != tc.match {
+			t.Errorf("Score(%q, %q): got match %v, want %v", tc.query, tc.candidate, ok, tc.match)
+		}
+	}
+}
+
+func TestScoreRanking(t *testing.T) {
+	// each query must score the better candidate higher than the worse one
+	tcs := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		{
+			// contiguous run
+			query:  "$(params.ab",
+			better: "$(params.abc)",
+			worse:  "$(params.a-b)",
+		},
+		{
+			// word boundary on `-`
+			query:  "gc",
+			better: "gen-code",
+			worse:  "agcx",
+		},
+		{
+			// word boundary on `.`
+			query:  "$(w.s",
+			better: "$(workspaces.source.path)",
+			worse:  "$(workspaces.ws.path)",
+		},
+		{
+			// camelCase boundary
+			query:  "tr",
+			better: "taskRun",
+			worse:  "tasker",
+		},
+		{
+			// exact case
+			query:  "Run",
+			better: "taskRun",
+			worse:  "taskrun",
+		},
+		{
+			// shorter leading gap
+			query:  "foo",
+			better: "foo-bar",
+			worse:  "bar-foo",
+		},
+	}
+	for _, tc := range tcs {
+		b, ok := Score(tc.query, tc.better)
+		if !ok {
+			t.Fatalf("Score(%q, %q): no match", tc.query, tc.better)
+		}
+		w, ok := Score(tc.query, tc.worse)
+		if !ok {
+			t.Fatalf("Score(%q, %q): no match", tc.query, tc.worse)
+		}
+		if b <= w {
+			t.Errorf("Score(%q): %q scored %d, not better than %q with %d", tc.query, tc.better, b, tc.worse, w)
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tcs := []struct {
+		query      string
+		candidates []fmt.Stringer
+		want       []fmt.Stringer
+	}{
+		{
+			query:      "",
+			candidates: texts("b", "a"),
+			want:       texts("b", "a"),
+		},
+		{
+			query: "$(params.re",
+			candidates: texts(
+				"$(params.context)",
+				"$(params.revision)",
+				"$(results.digest.path)",
+				"$(params.git-ref)",
+			),
+			want: texts(
+				"$(params.revision)",
+				"$(params.git-ref)",
+			),
+		},
+		{
+			query: "digest",
+			candidates: texts(
+				"$(tasks.build.results.image-digest)",
+				"$(results.digest.path)",
+				"$(params.revision)",
+			),
+			want: texts(
+				"$(results.digest.path)",
+				"$(tasks.build.results.image-digest)",
+			),
+		},
+	}
+	for _, tc := range tcs {
+		got := Solve(tc.query, tc.candidates)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Solve(%q): got %v, want %v", tc.query, got, tc.want)
+		}
+	}
+}
+
+// benchCandidates returns n candidates resembling the completions of a large
+// workspace.
+func benchCandidates(n int) []fmt.Stringer {
+	formats := []string{
+		"$(params.param-%d)",
+		"$(tasks.task-%d.results.digest)",
+		"$(workspaces.workspace%d.path)",
+		"$(steps.step-%d.exitCode.path)",
+		"pipeline-task-%d",
+	}
+	cs := make([]fmt.Stringer, n)
+	for i := range cs {
+		cs[i] = text(fmt.Sprintf(formats[i%len(formats)], i))
+	}
+	return cs
+}
+
+func BenchmarkSolve(b *testing.B) {
+	candidates := benchCandidates(5000)
+	for _, query := range []string{"", "$(", "$(tasks.t12.res", "dgst", "zzz"} {
+		b.Run(fmt.Sprintf("%q", query), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Solve(query, candidates)
+			}
+		})
+	}
+}
+
+func BenchmarkScore(b *testing.B) {
+	m := newMatcher("$(tasks.bld.res.dig")
+	for i := 0; i < b.N; i++ {
+		m.score("$(tasks.build-and-push.results.image-digest)")
+	}
+}
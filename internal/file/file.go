@@ -70,3 +70,14 @@ func (f TextDocument) LineOffset(line int) int {
 func (f TextDocument) PositionOffset(pos protocol.Position) int {
 	return f.LineOffset(int(pos.Line)) + int(pos.Character)
 }
+
+// CompletionStart returns the column in which the word being completed in
+// the given position starts. Words are delimited by spaces, dashes and double
+// quotes, but variable substitutions include their leading `$`.
+func (f TextDocument) CompletionStart(pos protocol.Position) int {
+	start := f.FindPrevious("$ -\"", pos) + 1
+	if start > 0 && f.GetLine(pos.Line)[start-1] == '$' {
+		start--
+	}
+	return start
+}
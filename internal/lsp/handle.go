@@ -0,0 +1,50 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/tekton"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// invalidParamsErrors is the list of errors reported to clients as invalid
+// params, along with their message, instead of as failed requests.
+var invalidParamsErrors = []error{
+	tekton.ErrInvalidRename,
+}
+
+// Handle implements glsp.Handler, serving the requests not dispatched by
+// protocol.Handler before delegating to it, and mapping the errors of
+// handlers to their JSON-RPC error codes.
+func (th *TektonHandler) Handle(context *glsp.Context) (r any, validMethod bool, validParams bool, err error) {
+	r, validMethod, validParams, err = th.handle(context)
+	for _, e := range invalidParamsErrors {
+		if errors.Is(err, e) {
+			validParams = false
+		}
+	}
+	return r, validMethod, validParams, err
+}
+
+func (th *TektonHandler) handle(context *glsp.Context) (r any, validMethod bool, validParams bool, err error) {
+	if context.Method == MethodTextDocumentInlayHint && th.IsInitialized() {
+		var params InlayHintParams
+		if err = json.Unmarshal(context.Params, &params); err != nil {
+			return nil, true, false, err
+		}
+		r, err = th.inlayHint(context, &params)
+		return r, true, true, err
+	}
+	if context.Method == protocol.MethodCodeLensResolve && th.IsInitialized() {
+		// protocol.Handler only dispatches it if TextDocumentDidClose is set
+		var params protocol.CodeLens
+		if err = json.Unmarshal(context.Params, &params); err != nil {
+			return nil, true, false, err
+		}
+		r, err = th.CodeLensResolve(context, &params)
+		return r, true, true, err
+	}
+	return th.Handler.Handle(context)
+}
@@ -0,0 +1,157 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/tekton"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// handle sends a request to the handler, as the JSON-RPC connection would.
+func handle(t *testing.T, th *TektonHandler, method string, params any) (any, bool, bool, error) {
+	t.Helper()
+	b, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return th.Handle(&glsp.Context{
+		Method: method,
+		Params: b,
+		Notify: func(string, any) {},
+	})
+}
+
+// newTestHandler returns a handler initialized with the given options, with
+// the given documents open.
+func newTestHandler(t *testing.T, opts any, docs map[string]string) *TektonHandler {
+	t.Helper()
+	th := NewTektonHandler("test")
+	_, _, _, err := handle(t, th, protocol.MethodInitialize, map[string]any{
+		"initializationOptions": opts,
+		"capabilities": map[string]any{
+			"textDocument": map[string]any{
+				"rename": map[string]any{"prepareSupport": true},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	for uri, text := range docs {
+		_, _, _, err := handle(t, th, protocol.MethodTextDocumentDidOpen, protocol.DidOpenTextDocumentParams{
+			TextDocument: protocol.TextDocumentItem{URI: uri, LanguageID: "yaml", Text: text},
+		})
+		if err != nil {
+			t.Fatalf("didOpen(%s): %v", uri, err)
+		}
+	}
+	return th
+}
+
+const testTask = `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: revision
+    default: main
+  - name: other
+  steps:
+  - name: build
+    image: busybox
+    script: echo $(params.revision) $(params.other)
+`
+
+func TestHandleInlayHint(t *testing.T) {
+	th := newTestHandler(t, nil, map[string]string{"file:///ws/task.yaml": testTask})
+
+	r, validMethod, validParams, err := handle(t, th, MethodTextDocumentInlayHint, InlayHintParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: "file:///ws/task.yaml"},
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 0, Character: 0},
+			End:   protocol.Position{Line: 100, Character: 0},
+		},
+	})
+	if !validMethod || !validParams || err != nil {
+		t.Fatalf("inlayHint: got (%v, %v, %v), want a valid request", validMethod, validParams, err)
+	}
+	want := []InlayHint{
+		{
+			Position: protocol.Position{Line: 12, Character: 35},
+			Label:    `: string = "main"`,
+			Kind:     InlayHintKindType,
+		},
+		{
+			Position: protocol.Position{Line: 12, Character: 51},
+			Label:    ": string",
+			Kind:     InlayHintKindType,
+		},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("inlayHint:\ngot  %+v\nwant %+v", r, want)
+	}
+}
+
+func TestHandleInvalidRename(t *testing.T) {
+	th := newTestHandler(t, nil, map[string]string{"file:///ws/task.yaml": testTask})
+
+	rename := func(name string) (bool, error) {
+		_, _, validParams, err := handle(t, th, protocol.MethodTextDocumentRename, protocol.RenameParams{
+			TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: "file:///ws/task.yaml"},
+				Position:     protocol.Position{Line: 6, Character: 10},
+			},
+			NewName: name,
+		})
+		return validParams, err
+	}
+
+	if validParams, err := rename("other"); validParams || !errors.Is(err, tekton.ErrInvalidRename) {
+		t.Errorf("rename(other): got (%v, %v), want invalid params", validParams, err)
+	}
+	if validParams, err := rename("commit"); !validParams || err != nil {
+		t.Errorf("rename(commit): got (%v, %v), want a valid request", validParams, err)
+	}
+}
+
+func TestHandleCodeLensCommand(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    any
+		command string
+	}{
+		{"without commands", nil, ""},
+		{"with other commands", initializationOptions{Commands: []string{"other"}}, ""},
+		{"with show references", initializationOptions{Commands: []string{tekton.CommandShowReferences}}, tekton.CommandShowReferences},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			th := newTestHandler(t, tc.opts, map[string]string{"file:///ws/task.yaml": testTask})
+			r, _, _, err := handle(t, th, protocol.MethodTextDocumentCodeLens, protocol.CodeLensParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: "file:///ws/task.yaml"},
+			})
+			if err != nil {
+				t.Fatalf("codeLens: %v", err)
+			}
+			lenses := r.([]protocol.CodeLens)
+			if len(lenses) == 0 {
+				t.Fatalf("codeLens: got no lenses")
+			}
+			r, _, _, err = handle(t, th, protocol.MethodCodeLensResolve, lenses[0])
+			if err != nil {
+				t.Fatalf("codeLens/resolve: %v", err)
+			}
+			cmd := r.(*protocol.CodeLens).Command
+			if cmd == nil || cmd.Title == "" {
+				t.Fatalf("codeLens/resolve: got command %v, want a title", cmd)
+			}
+			if cmd.Command != tc.command || (tc.command == "") != (cmd.Arguments == nil) {
+				t.Errorf("codeLens/resolve: got command %q with arguments %v, want %q", cmd.Command, cmd.Arguments, tc.command)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package lsp
+
+import (
+	"github.com/cezarguimaraes/tekton-ls/internal/tekton"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// MethodTextDocumentInlayHint is the inlay hint request, defined by LSP 3.17
+// and therefore not dispatched by protocol.Handler.
+const MethodTextDocumentInlayHint = "textDocument/inlayHint"
+
+// InlayHintParams are the parameters of an inlay hint request.
+type InlayHintParams struct {
+	TextDocument protocol.TextDocumentIdentifier `json:"textDocument"`
+	Range        protocol.Range                  `json:"range"`
+}
+
+// InlayHintKind is the kind of an InlayHint.
+type InlayHintKind int
+
+const (
+	InlayHintKindType      InlayHintKind = 1
+	InlayHintKindParameter InlayHintKind = 2
+)
+
+// InlayHint is a label rendered inline in the document. It's defined by LSP
+// 3.17, and therefore missing from protocol_3_16.
+type InlayHint struct {
+	Position     protocol.Position `json:"position"`
+	Label        string            `json:"label"`
+	Kind         InlayHintKind     `json:"kind,omitempty"`
+	PaddingLeft  bool              `json:"paddingLeft,omitempty"`
+	PaddingRight bool              `json:"paddingRight,omitempty"`
+}
+
+// serverCapabilities extends the LSP 3.16 capabilities with the ones
+// provided by handlers of later protocol versions.
+type serverCapabilities struct {
+	protocol.ServerCapabilities
+
+	InlayHintProvider bool `json:"inlayHintProvider,omitempty"`
+}
+
+// initializeResult is a protocol.InitializeResult with serverCapabilities.
+type initializeResult struct {
+	protocol.InitializeResult
+
+	Capabilities serverCapabilities `json:"capabilities"`
+}
+
+func (th *TektonHandler) inlayHint(context *glsp.Context, params *InlayHintParams) (any, error) {
+	f := getDoc(th, params.TextDocument)
+	if f == nil {
+		return nil, nil
+	}
+	res := []InlayHint{}
+	for _, h := range f.InlayHints(params.Range) {
+		hint := InlayHint{
+			Position: h.Position,
+			Label:    h.Label,
+		}
+		switch h.Kind {
+		case tekton.HintKindType:
+			hint.Kind = InlayHintKindType
+		case tekton.HintKindFile:
+			hint.PaddingLeft = true
+		}
+		res = append(res, hint)
+	}
+	return res, nil
+}
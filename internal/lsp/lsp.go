@@ -1,7 +1,9 @@
 package lsp
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/cezarguimaraes/tekton-ls/internal/completion"
 	"github.com/cezarguimaraes/tekton-ls/internal/tekton"
@@ -22,27 +24,47 @@ type TektonHandler struct {
 
 	workspace *tekton.Workspace
 
+	// commands is the set of client commands the server may send, as
+	// declared by the client in its initialization options.
+	commands map[string]struct{}
+
 	version string
 }
 
+// initializationOptions are the options sent by clients on initialize.
+type initializationOptions struct {
+	// Commands is the list of client commands the server may send, such as
+	// tekton.CommandShowReferences.
+	Commands []string `json:"commands"`
+}
+
 func NewTektonHandler(version string) *TektonHandler {
 	th := &TektonHandler{
 		workspace: tekton.NewWorkspace(),
 		version:   version,
 	}
 	th.Handler = protocol.Handler{
-		Initialize:                th.initialize(),
-		Initialized:               th.initialized(),
-		Shutdown:                  th.shutdown(),
-		SetTrace:                  th.setTrace(),
-		TextDocumentHover:         th.hover(),
-		TextDocumentDidOpen:       th.docOpen(),
-		TextDocumentDidChange:     th.docChange(),
-		TextDocumentCompletion:    th.docCompletion(),
-		TextDocumentDefinition:    th.definition(),
-		TextDocumentReferences:    th.references(),
-		TextDocumentPrepareRename: th.prepareRename(),
-		TextDocumentRename:        th.rename(),
+		Initialize:                      th.initialize(),
+		Initialized:                     th.initialized(),
+		Shutdown:                        th.shutdown(),
+		SetTrace:                        th.setTrace(),
+		TextDocumentHover:               th.hover(),
+		TextDocumentDidOpen:             th.docOpen(),
+		TextDocumentDidChange:           th.docChange(),
+		TextDocumentCompletion:          th.docCompletion(),
+		TextDocumentDefinition:          th.definition(),
+		TextDocumentReferences:          th.references(),
+		TextDocumentPrepareRename:       th.prepareRename(),
+		TextDocumentRename:              th.rename(),
+		TextDocumentDocumentHighlight:   th.documentHighlight(),
+		TextDocumentLinkedEditingRange:  th.linkedEditingRange(),
+		TextDocumentFoldingRange:        th.foldingRange(),
+		TextDocumentSelectionRange:      th.selectionRange(),
+		TextDocumentDocumentLink:        th.documentLink(),
+		TextDocumentCodeLens:            th.codeLens(),
+		CodeLensResolve:                 th.codeLensResolve(),
+		TextDocumentSemanticTokensFull:  th.semanticTokensFull(),
+		TextDocumentSemanticTokensRange: th.semanticTokensRange(),
 		// TODO: register workspace watch and listen for changes
 	}
 	return th
@@ -90,6 +112,15 @@ func (th *TektonHandler) initialize() protocol.InitializeFunc {
 			}
 		}
 
+		// code lens commands are computed on resolve
+		resolve := true
+		capabilities.CodeLensProvider.ResolveProvider = &resolve
+
+		capabilities.SemanticTokensProvider.(*protocol.SemanticTokensOptions).Legend = protocol.SemanticTokensLegend{
+			TokenTypes:     tekton.SemanticTokenTypes,
+			TokenModifiers: tekton.SemanticTokenModifiers,
+		}
+
 		capabilities.CompletionProvider.TriggerCharacters = []string{
 			".",
 			"(",
@@ -98,17 +129,38 @@ func (th *TektonHandler) initialize() protocol.InitializeFunc {
 			"\"",
 		}
 
+		// options are decoded as a generic JSON object
+		var opts initializationOptions
+		if params.InitializationOptions != nil {
+			b, err := json.Marshal(params.InitializationOptions)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(b, &opts); err != nil {
+				return nil, err
+			}
+		}
+		th.commands = map[string]struct{}{}
+		for _, c := range opts.Commands {
+			th.commands[c] = struct{}{}
+		}
+
 		// TODO: support rootUri and rootPath as well
 		for _, folder := range params.WorkspaceFolders {
 			th.workspace.AddFolder(folder.URI)
 		}
 		th.workspace.Lint()
 
-		return protocol.InitializeResult{
-			Capabilities: capabilities,
-			ServerInfo: &protocol.InitializeResultServerInfo{
-				Name:    lsName,
-				Version: &th.version,
+		return initializeResult{
+			InitializeResult: protocol.InitializeResult{
+				ServerInfo: &protocol.InitializeResultServerInfo{
+					Name:    lsName,
+					Version: &th.version,
+				},
+			},
+			Capabilities: serverCapabilities{
+				ServerCapabilities: capabilities,
+				InlayHintProvider:  true,
 			},
 		}, nil
 	}
@@ -139,45 +191,93 @@ func (th *TektonHandler) docCompletion() protocol.TextDocumentCompletionFunc {
 		var cs []protocol.CompletionItem
 		f := getDoc(th, params.TextDocument)
 
-		start := f.FindPrevious("$ -\"", params.Position)
-		if start == -1 {
-			return nil, nil
-		}
+		start := f.CompletionStart(params.Position)
 		line := f.GetLine(params.Position.Line)
-		if line[start] != '$' {
-			// don't include whitespace for contextual queries
-			start++
-		}
 		query := line[start:min(len(line), int(params.Position.Character))]
 
 		candidates := f.Completions(params.Position)
 
 		matches := completion.Solve(query, candidates)
-		kind := protocol.CompletionItemKindProperty
 		for idx, m := range matches {
-			preselect := idx == 0
-			cs = append(cs, protocol.CompletionItem{
-				Label:     m.String(),
-				Kind:      &kind,
-				Preselect: &preselect,
+			c := m.(tekton.CompletionCandidate)
+			label := c.String()
+			// nearer scopes first, keeping the order of matches otherwise
+			sortText := fmt.Sprintf("%d%05d", c.Rank, idx)
+			item := protocol.CompletionItem{
+				Label:    label,
+				Kind:     &c.Kind,
+				SortText: &sortText,
 				Documentation: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: m.(tekton.CompletionCandidate).Value.Documentation(),
+					Value: c.Value.Documentation(),
 				},
 				TextEdit: protocol.TextEdit{
-					NewText: m.String(),
+					NewText: label,
 					Range: protocol.Range{
 						Start: protocol.Position{Line: params.Position.Line, Character: uint32(start)},
 						End:   protocol.Position{Line: params.Position.Line, Character: params.Position.Character},
 					},
 				},
-			})
+			}
+			if prefix, name := namePrefix(query, label, c.Value.Name()); prefix != "" && c.Snippet == "" {
+				// only the name is replaced, so that clients filter by it
+				// rather than by the whole expression
+				item.FilterText = &name
+				item.TextEdit = protocol.TextEdit{
+					NewText: label[len(prefix):],
+					Range: protocol.Range{
+						Start: protocol.Position{Line: params.Position.Line, Character: uint32(start + len(prefix))},
+						End:   protocol.Position{Line: params.Position.Line, Character: params.Position.Character},
+					},
+				}
+			}
+			if c.Detail != "" {
+				item.Detail = &c.Detail
+			}
+			if c.Snippet != "" {
+				format := protocol.InsertTextFormatSnippet
+				mode := protocol.InsertTextModeAsIs
+				item.InsertTextFormat = &format
+				item.InsertTextMode = &mode
+				item.TextEdit = protocol.TextEdit{
+					NewText: c.Snippet,
+					Range:   item.TextEdit.(protocol.TextEdit).Range,
+				}
+			}
+			cs = append(cs, item)
 		}
 
 		return cs, nil
 	}
 }
 
+// namePrefix returns the prefix shared by the query and the label, up to the
+// last `.` or `[` typed, as `$(params.` when completing `$(params.fo` with
+// `$(params.foo)`, if the label continues with the given name as a whole
+// segment. The name is returned along with its opening quote, if any, as the
+// text to filter by. It returns an empty prefix otherwise.
+func namePrefix(query string, label string, name string) (string, string) {
+	i := strings.LastIndexAny(query, ".[")
+	if i == -1 || name == "" {
+		return "", ""
+	}
+	prefix := query[:i+1]
+	rest, ok := strings.CutPrefix(label, prefix)
+	if !ok {
+		return "", ""
+	}
+	quote := len(rest) - len(strings.TrimLeft(rest, `"'`))
+	after, ok := strings.CutPrefix(rest[quote:], name)
+	if !ok {
+		return "", ""
+	}
+	if after != "" && !strings.ContainsAny(after[:1], `).["'`) {
+		// the name is only the start of a segment of the label
+		return "", ""
+	}
+	return prefix, rest[:quote+len(name)]
+}
+
 func (th *TektonHandler) definition() protocol.TextDocumentDefinitionFunc {
 	return func(context *glsp.Context, params *protocol.DefinitionParams) (any, error) {
 		f := getDoc(th, params.TextDocument)
@@ -214,6 +314,75 @@ func (th *TektonHandler) references() protocol.TextDocumentReferencesFunc {
 	}
 }
 
+func (th *TektonHandler) documentHighlight() protocol.TextDocumentDocumentHighlightFunc {
+	return func(context *glsp.Context, params *protocol.DocumentHighlightParams) ([]protocol.DocumentHighlight, error) {
+		f := getDoc(th, params.TextDocument)
+		return f.DocumentHighlight(params.Position), nil
+	}
+}
+
+func (th *TektonHandler) linkedEditingRange() protocol.TextDocumentLinkedEditingRangeFunc {
+	return func(context *glsp.Context, params *protocol.LinkedEditingRangeParams) (*protocol.LinkedEditingRanges, error) {
+		f := getDoc(th, params.TextDocument)
+		ranges := f.LinkedEditingRanges(params.Position)
+		if ranges == nil {
+			return nil, nil
+		}
+		return &protocol.LinkedEditingRanges{Ranges: ranges}, nil
+	}
+}
+
+func (th *TektonHandler) foldingRange() protocol.TextDocumentFoldingRangeFunc {
+	return func(context *glsp.Context, params *protocol.FoldingRangeParams) ([]protocol.FoldingRange, error) {
+		f := getDoc(th, params.TextDocument)
+		return f.FoldingRanges(), nil
+	}
+}
+
+func (th *TektonHandler) selectionRange() protocol.TextDocumentSelectionRangeFunc {
+	return func(context *glsp.Context, params *protocol.SelectionRangeParams) ([]protocol.SelectionRange, error) {
+		f := getDoc(th, params.TextDocument)
+		return f.SelectionRanges(params.Positions), nil
+	}
+}
+
+func (th *TektonHandler) documentLink() protocol.TextDocumentDocumentLinkFunc {
+	return func(context *glsp.Context, params *protocol.DocumentLinkParams) ([]protocol.DocumentLink, error) {
+		f := getDoc(th, params.TextDocument)
+		return f.DocumentLinks(), nil
+	}
+}
+
+func (th *TektonHandler) codeLens() protocol.TextDocumentCodeLensFunc {
+	return func(context *glsp.Context, params *protocol.CodeLensParams) ([]protocol.CodeLens, error) {
+		f := getDoc(th, params.TextDocument)
+		return f.CodeLenses(), nil
+	}
+}
+
+func (th *TektonHandler) codeLensResolve() protocol.CodeLensResolveFunc {
+	return func(context *glsp.Context, params *protocol.CodeLens) (*protocol.CodeLens, error) {
+		// data is decoded as a generic JSON object
+		b, err := json.Marshal(params.Data)
+		if err != nil {
+			return nil, err
+		}
+		var data tekton.CodeLensData
+		if err := json.Unmarshal(b, &data); err != nil {
+			return nil, err
+		}
+		lens := th.workspace.ResolveCodeLens(*params, data)
+		if lens.Command != nil {
+			if _, ok := th.commands[lens.Command.Command]; !ok {
+				// clients can only show the title of commands they lack
+				lens.Command.Command = ""
+				lens.Command.Arguments = nil
+			}
+		}
+		return &lens, nil
+	}
+}
+
 func (th *TektonHandler) rename() protocol.TextDocumentRenameFunc {
 	return func(context *glsp.Context, params *protocol.RenameParams) (*protocol.WorkspaceEdit, error) {
 		return th.workspace.Rename(
@@ -254,3 +423,21 @@ func (th *TektonHandler) setTrace() protocol.SetTraceFunc {
 		return nil
 	}
 }
+
+func (th *TektonHandler) semanticTokensFull() protocol.TextDocumentSemanticTokensFullFunc {
+	return func(context *glsp.Context, params *protocol.SemanticTokensParams) (*protocol.SemanticTokens, error) {
+		f := getDoc(th, params.TextDocument)
+		return &protocol.SemanticTokens{
+			Data: f.SemanticTokens(nil),
+		}, nil
+	}
+}
+
+func (th *TektonHandler) semanticTokensRange() protocol.TextDocumentSemanticTokensRangeFunc {
+	return func(context *glsp.Context, params *protocol.SemanticTokensRangeParams) (any, error) {
+		f := getDoc(th, params.TextDocument)
+		return &protocol.SemanticTokens{
+			Data: f.SemanticTokens(&params.Range),
+		}, nil
+	}
+}
@@ -0,0 +1,27 @@
+package lsp
+
+import "testing"
+
+func TestNamePrefix(t *testing.T) {
+	tcs := []struct {
+		query, label, name string
+		prefix, filter     string
+	}{
+		{"$(params.fo", "$(params.foo)", "foo", "$(params.", "foo"},
+		{"$(params.", "$(params.foo)", "foo", "$(params.", "foo"},
+		{"$(params[", "$(params['foo.bar'])", "foo.bar", "$(params[", "'foo.bar"},
+		{"$(tasks.a.results.", "$(tasks.a.results.r.path)", "r", "$(tasks.a.results.", "r"},
+		{"$(tasks.a.res", "$(tasks.a.results.r)", "r", "", ""},
+		{"$(par", "$(params.foo)", "foo", "", ""},
+		{"$(workspaces.", "$(params.foo)", "foo", "", ""},
+	}
+	for _, tc := range tcs {
+		prefix, filter := namePrefix(tc.query, tc.label, tc.name)
+		if prefix != tc.prefix || filter != tc.filter {
+			t.Errorf(
+				"namePrefix(%q, %q, %q): got (%q, %q), want (%q, %q)",
+				tc.query, tc.label, tc.name, prefix, filter, tc.prefix, tc.filter,
+			)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package tekton
+
+import (
+	"fmt"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// CommandShowReferences is the client command which opens the references
+// list, given the URI, the position and the locations of the references.
+// It's registered by the VS Code extension, which converts the arguments
+// for `editor.action.showReferences`, and is only sent to clients declaring
+// it in their initialization options.
+const CommandShowReferences = "tekton.showReferences"
+
+// CodeLensData identifies the identifier of an unresolved code lens.
+type CodeLensData struct {
+	URI      string            `json:"uri"`
+	Position protocol.Position `json:"position"`
+}
+
+// codeLenses returns the unresolved code lenses of the document, above each
+// Task name and each parameter, result and workspace declaration.
+func (d *Document) codeLenses() []protocol.CodeLens {
+	var res []protocol.CodeLens
+	for _, id := range d.identifiers {
+		switch id.kind {
+		case IdentKindTask, IdentKindParam, IdentKindResult, IdentKindWorkspace:
+		default:
+			continue
+		}
+		res = append(res, protocol.CodeLens{
+			Range: id.location.Range,
+			Data: CodeLensData{
+				URI:      d.file.uri,
+				Position: id.location.Range.Start,
+			},
+		})
+	}
+	return res
+}
+
+// codeLensCommand returns the command of the code lens of an identifier:
+// the number of pipelines using a Task, or the number of references to
+// other identifiers, opening the references list.
+func (d *Document) codeLensCommand(id *identifier) *protocol.Command {
+	refs := wholeReferences(id)
+	if refs == nil {
+		refs = []protocol.Location{}
+	}
+
+	var title string
+	if id.kind == IdentKindTask {
+		pipelines := map[*Document]struct{}{}
+		for _, ref := range refs {
+			f := d.file.workspace.File(ref.URI)
+			if f == nil {
+				continue
+			}
+			rd := f.findDoc(ref.Range.Start)
+			if rd == nil {
+				continue
+			}
+			if rd.kind == "pipeline" {
+				pipelines[rd] = struct{}{}
+			}
+		}
+		title = fmt.Sprintf("used by %s", plural(len(pipelines), "pipeline"))
+	} else {
+		title = plural(len(refs), "reference")
+	}
+
+	return &protocol.Command{
+		Title:     title,
+		Command:   CommandShowReferences,
+		Arguments: []any{id.location.URI, id.location.Range.Start, refs},
+	}
+}
+
+// plural returns the count followed by the noun, pluralized unless the
+// count is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// CodeLenses returns the code lenses of the file, without their commands,
+// which are computed by ResolveCodeLens.
+func (f *File) CodeLenses() []protocol.CodeLens {
+	res := []protocol.CodeLens{}
+	if f.parseError != nil {
+		return res
+	}
+	for _, d := range f.docs {
+		res = append(res, d.codeLenses()...)
+	}
+	return res
+}
+
+// ResolveCodeLens fills the command of a code lens returned by CodeLenses.
+// The lens is returned unchanged if its identifier no longer exists.
+func (w *Workspace) ResolveCodeLens(lens protocol.CodeLens, data CodeLensData) protocol.CodeLens {
+	f := w.File(data.URI)
+	if f == nil || f.parseError != nil {
+		return lens
+	}
+	d := f.findDoc(data.Position)
+	if d == nil {
+		return lens
+	}
+	if id := d.findIdentifier(data.Position); id != nil {
+		lens.Command = d.codeLensCommand(id)
+	}
+	return lens
+}
@@ -0,0 +1,113 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestCodeLenses(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: foo
+  - name: unused
+  steps:
+  - name: echo
+    image: busybox
+    script: echo $(params.foo) $(params.foo)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: first
+    taskRef:
+      name: build
+  - name: second
+    taskRef:
+      name: build
+---
+apiVersion: tekton.dev/v1
+kind: TaskRun
+metadata:
+  name: run
+spec:
+  taskRef:
+    name: build
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: pipeline-run
+spec:
+  pipelineSpec:
+    tasks:
+    - name: inline
+      taskRef:
+        name: build
+`)
+	w.Lint()
+
+	lenses := w.File("file://task.yaml").CodeLenses()
+	titles := map[protocol.UInteger]string{}
+	args := map[protocol.UInteger][]any{}
+	for _, l := range lenses {
+		if l.Command != nil {
+			t.Errorf("CodeLenses: got command %v, want it unresolved", l.Command)
+		}
+		data, ok := l.Data.(CodeLensData)
+		if !ok {
+			t.Fatalf("CodeLenses: got data %v, want CodeLensData", l.Data)
+		}
+		l = w.ResolveCodeLens(l, data)
+		if l.Command == nil {
+			t.Fatalf("ResolveCodeLens: got no command for %v", l.Range)
+		}
+		if l.Command.Command != CommandShowReferences {
+			t.Errorf("ResolveCodeLens: got command %s, want %s", l.Command.Command, CommandShowReferences)
+		}
+		titles[l.Range.Start.Line] = l.Command.Title
+		args[l.Range.Start.Line] = l.Command.Arguments
+	}
+
+	want := map[protocol.UInteger]string{
+		3: "used by 1 pipeline",
+		6: "2 references",
+		7: "0 references",
+	}
+	for line, title := range want {
+		if titles[line] != title {
+			t.Errorf("line %d: got %q, want %q", line, titles[line], title)
+		}
+	}
+	if len(titles) != len(want) {
+		t.Errorf("got lenses %v, want %v", titles, want)
+	}
+
+	pos := protocol.Position{Line: 6, Character: 10}
+	loc := func(line, start, end protocol.UInteger) protocol.Location {
+		return protocol.Location{
+			URI: "file://task.yaml",
+			Range: protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			},
+		}
+	}
+	wantArgs := []any{
+		"file://task.yaml",
+		pos,
+		[]protocol.Location{loc(11, 17, 30), loc(11, 31, 44)},
+	}
+	if !reflect.DeepEqual(args[6], wantArgs) {
+		t.Errorf("line 6 arguments:\ngot  %v\nwant %v", args[6], wantArgs)
+	}
+}
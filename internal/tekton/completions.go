@@ -2,9 +2,11 @@ package tekton
 
 import (
 	"fmt"
+	"slices"
 
 	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
 	protocol "github.com/tliron/glsp/protocol_3_16"
 )
 
@@ -13,6 +15,18 @@ import (
 type completion struct {
 	context *yaml.Path
 	text    string
+
+	// scope is the YAML node from which context is evaluated. If nil, the
+	// context is evaluated from the document root.
+	scope ast.Node
+
+	// value is the Tekton object to which the completion refers. If nil,
+	// the identifier providing the completion is used.
+	value Meta
+
+	// kind is the LSP kind of the completion item. If zero, it's derived
+	// from the kind of the identifier providing the completion.
+	kind protocol.CompletionItemKind
 }
 
 // CompletionCandidate holds the text of a completion and the Tekton object
@@ -23,6 +37,22 @@ type CompletionCandidate struct {
 
 	// Value is the Tekton object to which the completion refers.
 	Value Meta
+
+	// Kind is the LSP kind of the completion item.
+	Kind protocol.CompletionItemKind
+
+	// Detail is a short description of Value, such as the type and default
+	// of a parameter.
+	Detail string
+
+	// Snippet, if set, is the LSP snippet inserted by the completion, with
+	// absolute indentation. Text is used as its label.
+	Snippet string
+
+	// Rank orders candidates by the scope they come from: contextual
+	// completions rank first, followed by identifiers declared in the
+	// document and, at last, built-in identifiers.
+	Rank int
 }
 
 // String implements fmt.Stringer for CompletionCandidate.
@@ -35,12 +65,16 @@ func (c CompletionCandidate) String() string {
 func (d *Document) completions(pos protocol.Position) []fmt.Stringer {
 	res := []fmt.Stringer{}
 
-	for _, id := range d.identifiers {
-		cs := id.meta.Completions()
+	for _, id := range slices.Concat(d.identifiers, d.builtins) {
+		cs := id.meta.Completions(d)
 		for _, c := range cs {
 			if c.context != nil {
 				// contextual completion
-				ctx, err := c.context.FilterNode(d.ast.Body)
+				scope := c.scope
+				if scope == nil {
+					scope = d.ast.Body
+				}
+				ctx, err := c.context.FilterNode(scope)
 				if err != nil {
 					continue
 				}
@@ -52,12 +86,88 @@ func (d *Document) completions(pos protocol.Position) []fmt.Stringer {
 					continue
 				}
 			}
+			value := c.value
+			if value == nil {
+				value = id.meta
+			}
+			kind := c.kind
+			if kind == 0 {
+				kind = id.kind.completionItemKind()
+			}
+			rank := 1
+			if c.context != nil {
+				rank = 0
+			} else if id.builtin {
+				rank = 2
+			}
 			res = append(res, CompletionCandidate{
-				Text:  c.text,
-				Value: id.meta,
+				Text:   c.text,
+				Value:  value,
+				Kind:   kind,
+				Detail: d.completionDetail(value),
+				Rank:   rank,
 			})
 		}
 	}
 
 	return res
 }
+
+// completionItemKind returns the LSP completion item kind used to suggest
+// identifiers of the kind.
+func (k identifierKind) completionItemKind() protocol.CompletionItemKind {
+	switch k {
+	case IdentKindParam:
+		return protocol.CompletionItemKindVariable
+	case IdentKindParamProperty:
+		return protocol.CompletionItemKindProperty
+	case IdentKindResult, IdentKindStepResult:
+		return protocol.CompletionItemKindField
+	case IdentKindWorkspace:
+		return protocol.CompletionItemKindFolder
+	case IdentKindPipelineTask, IdentKindTask, IdentKindPipeline:
+		return protocol.CompletionItemKindFunction
+	case IdentKindContext:
+		return protocol.CompletionItemKindConstant
+	case IdentKindStep, IdentKindSidecar:
+		return protocol.CompletionItemKindModule
+	}
+	return protocol.CompletionItemKindText
+}
+
+// completionDetail returns a short description of a Tekton object shown
+// next to its completions.
+func (d *Document) completionDetail(m Meta) string {
+	switch v := m.(type) {
+	case *identParam:
+		if v.HasDefault() {
+			return fmt.Sprintf("%s = %s", v.Type(), v.Default())
+		}
+		return v.Type()
+	case *paramProperty:
+		return v.Type()
+	case IdentResult:
+		return v.Type()
+	case IdentPipelineResult:
+		return v.Type()
+	case *stepResult:
+		return v.Type()
+	case IdentWorkspace:
+		if v.Optional() {
+			return "optional workspace"
+		}
+		return "workspace"
+	case *identStep:
+		return v.Image()
+	case *pipelineTask:
+		if d.file.workspace == nil || v.TaskRef() == "" {
+			return ""
+		}
+		id := d.file.workspace.getIdent(&kindNameLocator{IdentKindTask, v.TaskRef()})
+		if id == nil {
+			return v.TaskRef()
+		}
+		return fmt.Sprintf("%s (%s)", v.TaskRef(), relativeURI(d.file.uri, id.location.URI))
+	}
+	return ""
+}
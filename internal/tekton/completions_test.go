@@ -0,0 +1,256 @@
+package tekton
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func testWorkspace(t *testing.T) (*Workspace, string) {
+	t.Helper()
+	w := NewWorkspace()
+	cwd, _ := os.Getwd()
+	folder := "file://" + cwd + "/testdata/workspace"
+	w.AddFolder(folder)
+	w.Lint()
+	return w, folder
+}
+
+func completionTexts(f *File, pos protocol.Position) []string {
+	var texts []string
+	for _, c := range f.Completions(pos) {
+		texts = append(texts, c.String())
+	}
+	return texts
+}
+
+func TestPipelineTaskResultCompletions(t *testing.T) {
+	w, folder := testWorkspace(t)
+	pipe := w.File(folder + "/pipe.yaml")
+
+	got := completionTexts(pipe, protocol.Position{Line: 12, Character: 15})
+	for _, want := range []string{
+		"$(tasks.gen-code.results.foo)",
+		"$(tasks.gen-code-2.results.foo)",
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("Completions: %q not found in %v", want, got)
+		}
+	}
+}
+
+func TestPipelineTaskParamCompletions(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: revision
+    type: string
+    default: main
+  - name: context
+  - name: args
+    type: array
+    default: [-v, --debug]
+  steps:
+  - name: build
+    image: busybox
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    params:
+    - name: revision
+      value: main
+    - name: 
+  - name: other
+    taskRef:
+      name: build
+`)
+	w.Lint()
+	pipe := w.File("file://pipe.yaml")
+
+	tcs := []struct {
+		pos  protocol.Position
+		want []string
+		deny []string
+	}{
+		{
+			pos:  protocol.Position{Line: 11, Character: 12},
+			want: []string{"context", "args"},
+			deny: []string{"revision"},
+		},
+		{
+			pos:  protocol.Position{Line: 14, Character: 12},
+			deny: []string{"revision", "context", "args"},
+		},
+	}
+	for _, tc := range tcs {
+		got := completionTexts(pipe, tc.pos)
+		for _, want := range tc.want {
+			if !slices.Contains(got, want) {
+				t.Errorf("Completions(%v): %q not found in %v", tc.pos, want, got)
+			}
+		}
+		for _, deny := range tc.deny {
+			if slices.Contains(got, deny) {
+				t.Errorf("Completions(%v): unexpected %q in %v", tc.pos, deny, got)
+			}
+		}
+	}
+
+	found := false
+	for _, c := range pipe.Completions(protocol.Position{Line: 11, Character: 12}) {
+		if c.String() != "args" {
+			continue
+		}
+		found = true
+		cc := c.(CompletionCandidate)
+		if want := `array = ["-v","--debug"]`; cc.Detail != want {
+			t.Errorf("Completion args: got detail %q, want %q", cc.Detail, want)
+		}
+		if doc, want := cc.Value.Documentation(), `default: ["-v","--debug"]`; !strings.Contains(doc, want) {
+			t.Errorf("Completion args: got documentation %q, want %q", doc, want)
+		}
+	}
+	if !found {
+		t.Errorf("Completions: args not found")
+	}
+}
+
+func TestCompletionCandidates(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: revision
+    default: main
+  - name: args
+    type: array
+  workspaces:
+  - name: source
+    optional: true
+  results:
+  - name: digest
+  steps:
+  - name: build
+    image: busybox
+    script: echo
+`)
+	w.Lint()
+	f := w.File("file://task.yaml")
+
+	candidates := map[string]CompletionCandidate{}
+	for _, c := range f.Completions(protocol.Position{Line: 18, Character: 16}) {
+		candidates[c.String()] = c.(CompletionCandidate)
+	}
+
+	tcs := []struct {
+		text   string
+		kind   protocol.CompletionItemKind
+		detail string
+		rank   int
+	}{
+		{
+			text:   "$(params.revision)",
+			kind:   protocol.CompletionItemKindVariable,
+			detail: "string = main",
+			rank:   1,
+		},
+		{
+			text:   "$(params.args[*])",
+			kind:   protocol.CompletionItemKindVariable,
+			detail: "array",
+			rank:   1,
+		},
+		{
+			text:   "$(workspaces.source.path)",
+			kind:   protocol.CompletionItemKindFolder,
+			detail: "optional workspace",
+			rank:   1,
+		},
+		{
+			text:   "$(results.digest.path)",
+			kind:   protocol.CompletionItemKindField,
+			detail: "string",
+			rank:   1,
+		},
+		{
+			text:   "$(steps.build.exitCode.path)",
+			kind:   protocol.CompletionItemKindModule,
+			detail: "busybox",
+			rank:   1,
+		},
+		{
+			text: "$(context.taskRun.name)",
+			kind: protocol.CompletionItemKindConstant,
+			rank: 2,
+		},
+	}
+	for _, tc := range tcs {
+		c, ok := candidates[tc.text]
+		if !ok {
+			t.Errorf("Completions: %q not found", tc.text)
+			continue
+		}
+		if c.Kind != tc.kind || c.Detail != tc.detail || c.Rank != tc.rank {
+			t.Errorf(
+				"Completion %q: got kind %d, detail %q, rank %d, want %d, %q, %d",
+				tc.text, c.Kind, c.Detail, c.Rank, tc.kind, tc.detail, tc.rank,
+			)
+		}
+	}
+}
+
+func TestPipelineTaskCompletionDetail(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file:///ws/tasks/build.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  steps:
+  - name: build
+    image: busybox
+`)
+	w.UpsertFile("file:///ws/pipelines/pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: second
+    taskRef:
+      name: build
+    runAfter: [first]
+  - name: first
+    taskRef:
+      name: build
+`)
+	w.Lint()
+
+	for _, c := range w.File("file:///ws/pipelines/pipe.yaml").Completions(protocol.Position{Line: 9, Character: 16}) {
+		if c.String() != "first" {
+			continue
+		}
+		if got, want := c.(CompletionCandidate).Detail, "build (../tasks/build.yaml)"; got != want {
+			t.Errorf("Completion %q: got detail %q, want %q", c, got, want)
+		}
+		return
+	}
+	t.Errorf("Completions: pipeline task first not found")
+}
@@ -0,0 +1,146 @@
+package tekton
+
+import (
+	"fmt"
+
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// contextVariable is a variable provided by Tekton at runtime, such as
+// $(context.taskRun.name).
+type contextVariable struct {
+	name        string
+	description string
+
+	// contexts is the list of YAML paths in which the variable is available
+	// and suggested as a completion. If empty, it's available anywhere in
+	// the document.
+	contexts []*yaml.Path
+}
+
+var _ Meta = &contextVariable{}
+
+func (v *contextVariable) Completions(_ *Document) []completion {
+	text := fmt.Sprintf("$(context.%s)", v.name)
+	if len(v.contexts) == 0 {
+		return []completion{{text: text}}
+	}
+	var cs []completion
+	for _, ctx := range v.contexts {
+		cs = append(cs, completion{
+			text:    text,
+			context: ctx,
+		})
+	}
+	return cs
+}
+
+// availableAt returns whether the variable can be referred to at the given
+// offset of the document, that is, within one of its contexts if it has any.
+func (v *contextVariable) availableAt(d *Document, offset int) bool {
+	if len(v.contexts) == 0 {
+		return true
+	}
+	pos := d.OffsetPosition(offset)
+	for _, ctx := range v.contexts {
+		node, err := ctx.FilterNode(d.ast.Body)
+		if err != nil || node == nil {
+			continue
+		}
+		if yaml_helper.FindNode(node, int(pos.Line)+1, int(pos.Character)+1) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *contextVariable) Name() string {
+	return v.name
+}
+
+func (v *contextVariable) Documentation() string {
+	return fmt.Sprintf("`$(context.%s)`\n\n%s", v.name, v.description)
+}
+
+// taskContextVariables returns the context variables available to Tasks,
+// suggested only in the given YAML paths, if any.
+func taskContextVariables(contexts ...*yaml.Path) []*contextVariable {
+	return []*contextVariable{
+		{
+			name:        "taskRun.name",
+			description: "The name of the TaskRun that this Task is running in.",
+			contexts:    contexts,
+		},
+		{
+			name:        "taskRun.namespace",
+			description: "The namespace of the TaskRun that this Task is running in.",
+			contexts:    contexts,
+		},
+		{
+			name:        "taskRun.uid",
+			description: "The uid of the TaskRun that this Task is running in.",
+			contexts:    contexts,
+		},
+		{
+			name:        "task.name",
+			description: "The name of this Task.",
+			contexts:    contexts,
+		},
+		{
+			name:        "task.retry-count",
+			description: "The current retry number of this Task.",
+			contexts:    contexts,
+		},
+	}
+}
+
+// contextVariables maps a Tekton resource kind to the context variables
+// available in it. In Pipelines, Task variables are only available in
+// embedded task specs.
+var contextVariables = map[string][]*contextVariable{
+	"task": taskContextVariables(),
+	"pipeline": append([]*contextVariable{
+		{
+			name:        "pipelineRun.name",
+			description: "The name of the PipelineRun that this Pipeline is running in.",
+		},
+		{
+			name:        "pipelineRun.namespace",
+			description: "The namespace of the PipelineRun that this Pipeline is running in.",
+		},
+		{
+			name:        "pipelineRun.uid",
+			description: "The uid of the PipelineRun that this Pipeline is running in.",
+		},
+		{
+			name:        "pipeline.name",
+			description: "The name of this Pipeline.",
+		},
+		{
+			name:        "pipelineTask.retries",
+			description: "The retries of this PipelineTask.",
+		},
+	}, taskContextVariables(
+		mustPathString("$.spec.tasks[*].taskSpec"),
+		mustPathString("$.spec.finally[*].taskSpec"),
+	)...),
+}
+
+// addContextIdentifiers adds the built-in context variable identifiers
+// available to the document, according to its kind.
+func (d *Document) addContextIdentifiers() {
+	kind := d.kind
+	for _, v := range contextVariables[kind] {
+		d.builtins = append(d.builtins, &identifier{
+			kind:       IdentKindContext,
+			meta:       v,
+			parentKind: kind,
+			builtin:    true,
+			location: protocol.Location{
+				URI: d.file.uri,
+			},
+		})
+	}
+}
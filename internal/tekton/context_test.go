@@ -0,0 +1,98 @@
+package tekton
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestContextVariables(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  steps:
+  - name: echo
+    image: busybox
+    script: |
+      echo $(context.taskRun.name) $(context.task.retry-count)
+      echo $(context.taskrun.name) $(context.pipelineRun.name)
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: echo
+    params:
+    - name: run
+      value: $(context.pipelineRun.name)
+    - name: task-run
+      value: $(context.taskRun.name)
+    taskSpec:
+      steps:
+      - name: echo
+        image: busybox
+        script: echo $(context.taskRun.name) $(context.pipelineRun.name)
+`))
+
+	got := diagnosticMessages(f)
+	slices.Sort(got)
+	want := []string{
+		"unknown context pipelineRun.name",
+		"unknown context taskRun.name",
+		"unknown context taskrun.name",
+		"unused task hello",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	hover := f.Hover(protocol.Position{Line: 9, Character: 20})
+	if hover == nil || !strings.Contains(*hover, "The name of the TaskRun") {
+		t.Errorf("Hover: got %v, want taskRun.name documentation", hover)
+	}
+	if def := f.Definition(protocol.Position{Line: 9, Character: 20}); def != nil {
+		t.Errorf("Definition: got %v, want nil for context variables", def)
+	}
+
+	tcs := []struct {
+		pos  protocol.Position
+		want []string
+		deny []string
+	}{
+		{
+			pos:  protocol.Position{Line: 9, Character: 11},
+			want: []string{"$(context.taskRun.name)", "$(context.task.retry-count)"},
+			deny: []string{"$(context.pipelineRun.name)"},
+		},
+		{
+			pos:  protocol.Position{Line: 20, Character: 13},
+			want: []string{"$(context.pipelineRun.name)"},
+			deny: []string{"$(context.taskRun.name)"},
+		},
+		{
+			pos:  protocol.Position{Line: 27, Character: 21},
+			want: []string{"$(context.pipelineRun.name)", "$(context.taskRun.name)"},
+		},
+	}
+	for _, tc := range tcs {
+		got := completionTexts(f, tc.pos)
+		for _, want := range tc.want {
+			if !slices.Contains(got, want) {
+				t.Errorf("Completions(%v): %q not found in %v", tc.pos, want, got)
+			}
+		}
+		for _, deny := range tc.deny {
+			if slices.Contains(got, deny) {
+				t.Errorf("Completions(%v): unexpected %q in %v", tc.pos, deny, got)
+			}
+		}
+	}
+}
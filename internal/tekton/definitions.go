@@ -6,7 +6,7 @@ import protocol "github.com/tliron/glsp/protocol_3_16"
 // in a given position, or nil if none is found in this document.
 func (d *Document) definition(pos protocol.Position) *protocol.Location {
 	ref := d.referenceInPosition(pos)
-	if ref == nil || ref.ident == nil {
+	if ref == nil || ref.ident == nil || ref.ident.builtin {
 		return nil
 	}
 	return &ref.ident.location
@@ -25,7 +25,9 @@ func (d *Document) hover(pos protocol.Position) *string {
 
 // referenceInPosition searches for a reference in the document in the given
 // position. A reference is any text fragment which might refer to an identifier.
+// If references overlap, as in $(params.name.key), the narrowest is returned.
 func (d *Document) referenceInPosition(pos protocol.Position) *reference {
+	var res *reference
 	for _, ref := range d.references {
 		// assuming ref.start.Line = ref.end.Line
 		if ref.start.Line != pos.Line {
@@ -37,7 +39,10 @@ func (d *Document) referenceInPosition(pos protocol.Position) *reference {
 		if pos.Character < ref.start.Character {
 			continue
 		}
-		return &ref
+		if res != nil && res.end.Character-res.start.Character <= ref.end.Character-ref.start.Character {
+			continue
+		}
+		res = &ref
 	}
-	return nil
+	return res
 }
@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml"
 	protocol "github.com/tliron/glsp/protocol_3_16"
 )
 
 // diagnostics sends into the argument channel any problems identified
-// in the document. It currently only reports references for which none
-// identifier has been found.
+// in the document: references for which no identifier has been found,
+// unused identifiers, invalid pipeline tasks, parameter type mismatches,
+// invalid steps, when expressions, matrices and pipeline results.
 func (d *Document) diagnostics(c chan<- *protocol.Diagnostic) {
 	for _, ref := range d.references {
 		if ref.ident != nil {
@@ -38,6 +42,26 @@ func (d *Document) diagnostics(c chan<- *protocol.Diagnostic) {
 		if id.kind == IdentKindPipelineTask {
 			continue
 		}
+		// pipelines are commonly run without a PipelineRun in the workspace
+		if id.kind == IdentKindPipeline {
+			continue
+		}
+		if id.kind == IdentKindStep || id.kind == IdentKindSidecar {
+			continue
+		}
+		// results with a value are set from step results instead of
+		// being written to $(results.name.path)
+		if r, ok := id.meta.(IdentResult); ok && r.HasValue() {
+			continue
+		}
+		// pipeline results are outputs of the PipelineRun
+		if _, ok := id.meta.(IdentPipelineResult); ok {
+			continue
+		}
+		// properties are commonly used by passing the whole object
+		if id.kind == IdentKindParamProperty {
+			continue
+		}
 
 		sev := protocol.DiagnosticSeverityWarning
 		src := fmt.Sprintf("unused-%s", id.kind)
@@ -49,6 +73,242 @@ func (d *Document) diagnostics(c chan<- *protocol.Diagnostic) {
 			Source:   &src,
 		}
 	}
+	d.pipelineTaskDiagnostics(c)
+	d.paramTypeDiagnostics(c)
+	d.stepDiagnostics(c)
+	d.whenDiagnostics(c)
+	d.matrixDiagnostics(c)
+	d.pipelineResultDiagnostics(c)
+}
+
+// newDiagnostic builds a Diagnostic of the given severity and source.
+func newDiagnostic(
+	r protocol.Range,
+	sev protocol.DiagnosticSeverity,
+	src string,
+	format string,
+	args ...any,
+) *protocol.Diagnostic {
+	return &protocol.Diagnostic{
+		Range:    r,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: &sev,
+		Source:   &src,
+	}
+}
+
+// pipelineTaskDiagnostics reports parameters without a default value and
+// non-optional workspaces declared by the Task referenced by a pipeline task
+// which are not passed, or bound, by it. It also reports parameter values
+// whose type doesn't match the type declared by the Task.
+func (d *Document) pipelineTaskDiagnostics(c chan<- *protocol.Diagnostic) {
+	for _, id := range d.identifiers {
+		pt, ok := id.meta.(*pipelineTask)
+		if !ok {
+			continue
+		}
+
+		set := pt.paramNames()
+		for _, pid := range pt.taskParams(d.file.workspace) {
+			p, ok := pid.meta.(*identParam)
+			if !ok || p.HasDefault() {
+				continue
+			}
+			if _, ok := set[p.Name()]; ok {
+				continue
+			}
+			c <- newDiagnostic(
+				id.location.Range,
+				protocol.DiagnosticSeverityError,
+				"missing-parameter",
+				"missing parameter %s required by task %s",
+				p.Name(),
+				pt.TaskRef(),
+			)
+		}
+
+		bound := pt.workspaceNames()
+		for _, wid := range pt.taskWorkspaces(d.file.workspace) {
+			ws, ok := wid.meta.(IdentWorkspace)
+			if !ok || ws.Optional() {
+				continue
+			}
+			if _, ok := bound[ws.Name()]; ok {
+				continue
+			}
+			c <- newDiagnostic(
+				id.location.Range,
+				protocol.DiagnosticSeverityError,
+				"missing-workspace",
+				"missing workspace %s required by task %s",
+				ws.Name(),
+				pt.TaskRef(),
+			)
+		}
+
+		d.pipelineTaskParamValueDiagnostics(pt, c)
+	}
+}
+
+// pipelineTaskParamValueDiagnostics reports values passed by a pipeline task
+// which can't be assigned to the type of the Task parameter. String values
+// are always accepted since they may contain variable substitutions.
+func (d *Document) pipelineTaskParamValueDiagnostics(
+	pt *pipelineTask,
+	c chan<- *protocol.Diagnostic,
+) {
+	taskName := pt.TaskRef()
+	if taskName == "" {
+		return
+	}
+	paths := []*yaml.Path{mustPathString("$.params[*]")}
+	yaml_helper.VisitPath(pt.node, paths, func(nodes []yaml_helper.ParsedNode) {
+		pm, ok := nodes[1].Value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		name, _ := pm["name"].(string)
+		id := d.file.workspace.getIdent(&taskParamLocator{
+			name:     name,
+			taskName: taskName,
+		})
+		if id == nil {
+			return
+		}
+		p, ok := id.meta.(*identParam)
+		if !ok {
+			return
+		}
+
+		var got string
+		switch pm["value"].(type) {
+		case []interface{}:
+			got = "array"
+		case map[string]interface{}:
+			got = "object"
+		default:
+			return
+		}
+		if got == p.Type() {
+			return
+		}
+
+		// the value node itself can't be visited since sequences are
+		// expanded by yaml.VisitPath
+		valueNode, err := mustPathString("$.value").FilterNode(nodes[1].Node)
+		if err != nil || valueNode == nil {
+			return
+		}
+		r, _ := d.getNodeRange(valueNode)
+		c <- newDiagnostic(
+			r,
+			protocol.DiagnosticSeverityError,
+			"parameter-type",
+			"parameter %s of task %s expects type %s, got %s",
+			name,
+			taskName,
+			p.Type(),
+			got,
+		)
+	})
+}
+
+// paramTypeDiagnostics reports parameter substitutions which can't be applied
+// to the type of the parameter they refer to.
+func (d *Document) paramTypeDiagnostics(c chan<- *protocol.Diagnostic) {
+	for _, ref := range d.references {
+		if ref.kind != IdentKindParam || !ref.substitution || ref.ident == nil {
+			continue
+		}
+		p, ok := ref.ident.meta.(*identParam)
+		if !ok {
+			continue
+		}
+
+		var msg string
+		switch {
+		case strings.HasPrefix(ref.accessor, "."):
+			// only the first key is checked, as in $(params.obj.key.more)
+			key, _, _ := strings.Cut(strings.TrimPrefix(ref.accessor, "."), ".")
+			if p.Type() != "object" {
+				msg = fmt.Sprintf("%s parameter %s has no key %s", p.Type(), p.Name(), key)
+			} else if _, ok := p.Properties()[key]; !ok {
+				msg = fmt.Sprintf("object parameter %s has no key %s", p.Name(), key)
+			}
+		case ref.accessor == "[*]":
+			if p.Type() == "string" {
+				msg = fmt.Sprintf("string parameter %s can't be expanded with [*]", p.Name())
+			}
+		case strings.HasPrefix(ref.accessor, "["):
+			if p.Type() != "array" {
+				msg = fmt.Sprintf("%s parameter %s can't be indexed", p.Type(), p.Name())
+			}
+		case p.Type() == "array":
+			msg = fmt.Sprintf(
+				"array parameter %s used as a string, use $(%s[*])",
+				p.Name(),
+				p.path(),
+			)
+		case p.Type() == "object":
+			msg = fmt.Sprintf(
+				"object parameter %s used as a string, use one of its keys or $(%s[*])",
+				p.Name(),
+				p.path(),
+			)
+		}
+		if msg == "" {
+			continue
+		}
+
+		c <- newDiagnostic(
+			protocol.Range{Start: ref.start, End: ref.end},
+			protocol.DiagnosticSeverityError,
+			"parameter-type",
+			"%s",
+			msg,
+		)
+	}
+}
+
+// stepDiagnostics reports duplicate step, sidecar and step result names, as
+// well as step results of an unknown type.
+func (d *Document) stepDiagnostics(c chan<- *protocol.Diagnostic) {
+	seen := map[string]struct{}{}
+	for _, id := range d.identifiers {
+		var key string
+		switch m := id.meta.(type) {
+		case *identStep:
+			key = fmt.Sprintf("%s/%s", id.kind, m.Name())
+		case *stepResult:
+			key = fmt.Sprintf("%s/%s/%s", id.kind, m.step, m.Name())
+			switch m.Type() {
+			case "string", "array", "object":
+			default:
+				c <- newDiagnostic(
+					id.location.Range,
+					protocol.DiagnosticSeverityError,
+					"invalid-stepResult",
+					"step result %s has unknown type %s",
+					m.Name(),
+					m.Type(),
+				)
+			}
+		default:
+			continue
+		}
+
+		if _, ok := seen[key]; ok {
+			c <- newDiagnostic(
+				id.location.Range,
+				protocol.DiagnosticSeverityError,
+				fmt.Sprintf("duplicate-%s", id.kind),
+				"duplicate %s %s",
+				id.kind,
+				id.meta.Name(),
+			)
+		}
+		seen[key] = struct{}{}
+	}
 }
 
 var syntaxErrorRegexp = regexp.MustCompile(`(?s)^\[(\d+):(\d+)\] (.+)`)
@@ -1,9 +1,11 @@
 package tekton
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
 )
 
 func TestDocDiagnostics(t *testing.T) {
@@ -13,3 +15,203 @@ func TestDocDiagnostics(t *testing.T) {
 
 	// f.Completions(protocol.Position{Line: 19, Character: 10})
 }
+
+// diagnosticMessages returns the messages of every diagnostic reported
+// for the given File.
+func diagnosticMessages(f *File) []string {
+	var msgs []string
+	for _, dg := range f.Diagnostics() {
+		msgs = append(msgs, dg.Message)
+	}
+	return msgs
+}
+
+func TestPipelineTaskParamDiagnostics(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: revision
+    default: main
+  - name: context
+  - name: args
+    type: array
+    default: []
+  steps:
+  - name: build
+    image: busybox
+    args: ["$(params.revision)", "$(params.context)", "$(params.args[*])"]
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    params:
+    - name: revision
+      value: main
+    - name: unknown
+      value: foo
+  - name: complete
+    taskRef:
+      name: build
+    params:
+    - name: context
+      value: .
+  - name: remote
+    taskRef:
+      resolver: git
+    params:
+    - name: url
+      value: https://example.com
+`)
+	w.Lint()
+
+	got := diagnosticMessages(w.File("file://pipe.yaml"))
+	want := []string{
+		"missing parameter context required by task build",
+		"unknown parameter unknown",
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+}
+
+func TestPipelineTaskWorkspaceDiagnostics(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  workspaces:
+  - name: source
+  - name: cache
+    optional: true
+  - name: credentials
+  steps:
+  - name: build
+    image: busybox
+    script: |
+      ls $(workspaces.source.path) $(workspaces.cache.path)
+      ls $(workspaces.credentials.path)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  workspaces:
+  - name: shared
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    workspaces:
+    - name: source
+      workspace: shared
+    - name: output
+      workspace: shared
+`)
+	w.Lint()
+
+	got := diagnosticMessages(w.File("file://pipe.yaml"))
+	want := []string{
+		"missing workspace credentials required by task build",
+		"unknown workspace output",
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	pos := protocol.Position{Line: 12, Character: 13}
+	def := w.File("file://pipe.yaml").Definition(pos)
+	if def == nil || def.URI != "file://task.yaml" || def.Range.Start.Line != 6 {
+		t.Errorf("Definition(%v): got %v, want task.yaml line 6", pos, def)
+	}
+}
+
+func TestParamTypeDiagnostics(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: revision
+  - name: flags
+    type: array
+  - name: git
+    type: object
+    properties:
+      url: {}
+      commit: {}
+  - name: build.flags
+    type: array
+    default: []
+  steps:
+  - name: build
+    image: busybox
+    args:
+    - $(params.flags[*])
+    - $(params.flags[0])
+    - $(params.revision)
+    - $(params.git.url)
+    script: |
+      echo $(params.flags) $(params.revision[*]) $(params.revision[1])
+      echo $(params.git.branch) $(params.revision.key) $(params.git.commit)
+      echo $(params.git) $(params["build.flags"]) $(params.git.url.host)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    params:
+    - name: revision
+      value: [main]
+    - name: flags
+      value: [-v]
+    - name: git
+      value:
+        url: https://example.com
+`)
+	w.Lint()
+
+	got := diagnosticMessages(w.File("file://task.yaml"))
+	want := []string{
+		"array parameter build.flags used as a string, use $(params[\"build.flags\"][*])",
+		"array parameter flags used as a string, use $(params.flags[*])",
+		"object parameter git has no key branch",
+		"object parameter git used as a string, use one of its keys or $(params.git[*])",
+		"string parameter revision can't be expanded with [*]",
+		"string parameter revision can't be indexed",
+		"string parameter revision has no key key",
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	got = diagnosticMessages(w.File("file://pipe.yaml"))
+	want = []string{
+		"parameter revision of task build expects type string, got array",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+}
@@ -94,6 +94,12 @@ var singleTCs = []identTC{
 		defLine: 4,
 		defCol:  9,
 	},
+	{
+		kind:    IdentKindStep,
+		name:    "echo",
+		defLine: 18,
+		defCol:  13,
+	},
 }
 
 var pipeTCs = []identTC{
@@ -131,6 +137,12 @@ var pipeTCs = []identTC{
 		defLine: 18,
 		defCol:  13,
 	},
+	{
+		kind:    IdentKindPipeline,
+		name:    "pipeline",
+		defLine: 4,
+		defCol:  9,
+	},
 }
 
 var taskTCs = []identTC{
@@ -167,6 +179,16 @@ var taskTCs = []identTC{
 		name:    "source",
 		defLine: 37,
 		defCol:  11,
+		refs: []protocol.Range{
+			{
+				Start: protocol.Position{Line: 15, Character: 16},
+				End:   protocol.Position{Line: 15, Character: 22},
+			},
+			{
+				Start: protocol.Position{Line: 23, Character: 16},
+				End:   protocol.Position{Line: 23, Character: 22},
+			},
+		},
 	},
 	{
 		kind:    IdentKindTask,
@@ -184,6 +206,12 @@ var taskTCs = []identTC{
 			},
 		},
 	},
+	{
+		kind:    IdentKindStep,
+		name:    "example",
+		defLine: 39,
+		defCol:  13,
+	},
 }
 
 func TestDocParseIdentifiers(t *testing.T) {
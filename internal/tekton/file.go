@@ -26,8 +26,8 @@ type Meta interface {
 	Documentation() string
 
 	// Completions returns a list of possible completion suggestions
-	// associated with the Tekton object.
-	Completions() []completion
+	// associated with the Tekton object, as seen from the given Document.
+	Completions(*Document) []completion
 }
 
 // File provides operation on a YAML file containing any number of
@@ -72,11 +72,23 @@ type Document struct {
 	// ast is the abstract syntax tree of this YAML document.
 	ast *ast.DocumentNode
 
+	// kind is the lowercase kind of the Tekton resource in this document,
+	// such as "task", or empty if it has none.
+	kind string
+
 	// identifiers is the list of identifiers (i.e definitions) in this file.
 	identifiers []*identifier
 
+	// builtins is the list of identifiers provided by Tekton which are
+	// available in this document, such as context variables.
+	builtins []*identifier
+
 	// references is the list of possible references to identifiers in this file.
 	references []reference
+
+	// substitutions is the list of variable substitution expressions in
+	// this document.
+	substitutions []substitution
 }
 
 // helmSanitizerRegexp is the regular expression used to identify Helm template
@@ -113,6 +125,9 @@ func NewFile(f file.TextDocument) *File {
 			references:  []reference{},
 			identifiers: []*identifier{},
 		}
+		if node, err := mustPathString("$.kind").FilterNode(doc.Body); err == nil && node != nil {
+			d.kind = strings.ToLower(node.GetToken().Value)
+		}
 		if i > 0 {
 			r.docs[i-1].size = d.offset - r.docs[i-1].offset
 		}
@@ -177,6 +192,16 @@ func (f *File) getIdent(l identLocator) *identifier {
 	return ids[0]
 }
 
+// getIdents returns every identifier in this File matched by the given
+// locator.
+func (f *File) getIdents(l identLocator) []*identifier {
+	var ids []*identifier
+	for _, d := range f.docs {
+		ids = append(ids, d.getIdents(l)...)
+	}
+	return ids
+}
+
 // findDoc returns the Document containing the given position.
 func (f *File) findDoc(pos protocol.Position) *Document {
 	for _, d := range f.docs {
@@ -221,6 +246,56 @@ func (f *File) FindReferences(pos protocol.Position) []protocol.Location {
 	return f.findDoc(pos).findReferences(pos)
 }
 
+// DocumentHighlight returns the occurrences in this File of the identifier
+// in the given position, or nil if no identifier is found.
+func (f *File) DocumentHighlight(pos protocol.Position) []protocol.DocumentHighlight {
+	d := f.findDoc(pos)
+	if d == nil {
+		return nil
+	}
+	return d.documentHighlight(pos)
+}
+
+// LinkedEditingRanges returns the ranges of the name of the identifier in
+// the given position which can be edited together, or nil if there are none.
+func (f *File) LinkedEditingRanges(pos protocol.Position) []protocol.Range {
+	d := f.findDoc(pos)
+	if d == nil {
+		return nil
+	}
+	return d.linkedEditingRanges(pos)
+}
+
+// FoldingRanges returns the ranges which can be folded in this File.
+func (f *File) FoldingRanges() []protocol.FoldingRange {
+	res := []protocol.FoldingRange{}
+	if f.parseError != nil {
+		return res
+	}
+	for _, d := range f.docs {
+		res = append(res, d.foldingRanges()...)
+	}
+	return res
+}
+
+// SelectionRanges returns, for each of the given positions, the ranges
+// enclosing it, from the innermost to the whole document. Positions out of
+// any document are given an empty range.
+func (f *File) SelectionRanges(positions []protocol.Position) []protocol.SelectionRange {
+	res := make([]protocol.SelectionRange, 0, len(positions))
+	for _, pos := range positions {
+		var r *protocol.SelectionRange
+		if d := f.findDoc(pos); f.parseError == nil && d != nil {
+			r = d.selectionRange(pos)
+		}
+		if r == nil {
+			r = &protocol.SelectionRange{Range: protocol.Range{Start: pos, End: pos}}
+		}
+		res = append(res, *r)
+	}
+	return res
+}
+
 // Completions returns a list of completion suggestions for the given
 // position.
 func (f *File) Completions(pos protocol.Position) []fmt.Stringer {
@@ -228,7 +303,10 @@ func (f *File) Completions(pos protocol.Position) []fmt.Stringer {
 	if f.parseError != nil {
 		return res
 	}
-	return f.findDoc(pos).completions(pos)
+	if d := f.findDoc(pos); d != nil {
+		res = d.completions(pos)
+	}
+	return append(res, f.snippetCompletions(pos)...)
 }
 
 // Diagnostics returns a list of Diagnostics issues found in this File.
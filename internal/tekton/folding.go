@@ -0,0 +1,103 @@
+package tekton
+
+import (
+	"strings"
+
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// foldingPaths is the list of paths, as in yaml_helper.VisitPath, of the
+// list items folded individually, such as steps and pipeline tasks.
+var foldingPaths = [][]*yaml.Path{
+	{mustPathString("$.spec.steps[*]")},
+	{mustPathString("$.spec.sidecars[*]")},
+	{mustPathString("$.spec.tasks[*]")},
+	{mustPathString("$.spec.finally[*]")},
+	{mustPathString("$.spec.tasks[*]"), mustPathString("$.taskSpec.steps[*]")},
+	{mustPathString("$.spec.finally[*]"), mustPathString("$.taskSpec.steps[*]")},
+	{mustPathString("$.spec.taskSpec.steps[*]")},
+	{mustPathString("$.spec.pipelineSpec.tasks[*]")},
+	{mustPathString("$.spec.pipelineSpec.finally[*]")},
+}
+
+// foldingRanges returns the ranges which can be folded in the document: the
+// whole document, each step and pipeline task, and each multi-line script.
+func (d *Document) foldingRanges() []protocol.FoldingRange {
+	var res []protocol.FoldingRange
+	add := func(start, end protocol.UInteger) {
+		if end > start {
+			res = append(res, protocol.FoldingRange{
+				StartLine: start,
+				EndLine:   end,
+			})
+		}
+	}
+
+	lines := strings.Split(string(d.TextDocument), "\n")
+	start := d.OffsetPosition(d.offset).Line
+	// the document ends before the first line of the next one
+	end := d.OffsetPosition(max(d.offset, d.offset+d.size-1)).Line
+	add(start, lastContentLine(lines, start, end))
+
+	for _, paths := range foldingPaths {
+		yaml_helper.VisitPath(d.ast.Body, paths, func(nodes []yaml_helper.ParsedNode) {
+			item := nodes[len(nodes)-1].Node
+			mvs := yaml_helper.MappingValues(item)
+			if len(mvs) == 0 {
+				return
+			}
+			line, col := keyPosition(mvs[0])
+			add(line, blockEnd(lines, line, col))
+
+			for _, mv := range mvs {
+				if mv.Key.GetToken().Value != "script" {
+					continue
+				}
+				line, col := keyPosition(mv)
+				add(line, blockEnd(lines, line, col+1))
+			}
+		})
+	}
+	return res
+}
+
+// keyPosition returns the zero-based line and column of the key of a
+// mapping entry.
+func keyPosition(mv *ast.MappingValueNode) (protocol.UInteger, int) {
+	pos := mv.Key.GetToken().Position
+	return protocol.UInteger(pos.Line - 1), pos.Column - 1
+}
+
+// blockEnd returns the last of the lines of the block starting at line,
+// which spans the following lines indented at least as much as indent.
+// Blank lines and comments don't end the block, but are not included at its
+// end.
+func blockEnd(lines []string, line protocol.UInteger, indent int) protocol.UInteger {
+	end := line
+	for l := line + 1; int(l) < len(lines); l++ {
+		i, _, ok := lineIndent(lines[l])
+		if !ok {
+			continue
+		}
+		if i < indent {
+			break
+		}
+		end = l
+	}
+	return end
+}
+
+// lastContentLine returns the last of the lines between start and end,
+// inclusive, which is neither blank, a comment nor a document separator.
+func lastContentLine(lines []string, start, end protocol.UInteger) protocol.UInteger {
+	for l := min(end, protocol.UInteger(len(lines)-1)); l > start; l-- {
+		text := lines[l]
+		if _, _, ok := lineIndent(text); ok && !strings.HasPrefix(text, "---") {
+			return l
+		}
+	}
+	return start
+}
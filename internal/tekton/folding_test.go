@@ -0,0 +1,69 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestFoldingRanges(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  steps:
+  - name: echo
+    image: busybox
+    script: |
+      echo hello
+
+      echo world
+  # a comment
+  - image: busybox
+    name: one-line
+    script: echo
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: hello
+    taskRef:
+      name: hello
+  finally:
+  - name: cleanup
+    taskSpec:
+      steps:
+      - name: rm
+        image: busybox
+        script: |-
+          rm -rf /tmp
+`))
+
+	type lines struct{ start, end protocol.UInteger }
+	var got []lines
+	for _, r := range f.FoldingRanges() {
+		got = append(got, lines{r.StartLine, r.EndLine})
+	}
+	want := []lines{
+		// Task
+		{0, 15},
+		{6, 11},  // step echo
+		{8, 11},  // its script
+		{13, 15}, // step one-line
+		// Pipeline
+		{17, 33},
+		{23, 25}, // task hello
+		{27, 33}, // task cleanup
+		{30, 33}, // step rm
+		{32, 33}, // its script
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FoldingRanges:\ngot  %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package tekton
+
+import protocol "github.com/tliron/glsp/protocol_3_16"
+
+// identifierAt returns the identifier defined or referred to in the given
+// position, or nil if there is none.
+func (d *Document) identifierAt(pos protocol.Position) *identifier {
+	if id := d.findIdentifier(pos); id != nil {
+		return id
+	}
+	if ref := d.referenceInPosition(pos); ref != nil {
+		return ref.ident
+	}
+	return nil
+}
+
+// documentHighlight returns the occurrences in the file of the identifier
+// defined or referred to in the given position: its definition, written,
+// and its references, read.
+func (d *Document) documentHighlight(pos protocol.Position) []protocol.DocumentHighlight {
+	id := d.identifierAt(pos)
+	if id == nil {
+		return nil
+	}
+
+	var res []protocol.DocumentHighlight
+	if !id.builtin && id.location.URI == d.file.uri {
+		kind := protocol.DocumentHighlightKindWrite
+		res = append(res, protocol.DocumentHighlight{
+			Range: id.location.Range,
+			Kind:  &kind,
+		})
+	}
+	for _, ref := range id.references {
+		if ref[1].URI != d.file.uri {
+			continue
+		}
+		kind := protocol.DocumentHighlightKindRead
+		res = append(res, protocol.DocumentHighlight{
+			Range: ref[1].Range,
+			Kind:  &kind,
+		})
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestDocumentHighlight(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  params:
+  - name: foo
+  steps:
+  - name: echo
+    image: busybox
+    script: |
+      echo $(params.foo) $(params.foo) $(context.task.name)
+`))
+
+	write := protocol.DocumentHighlightKindWrite
+	read := protocol.DocumentHighlightKindRead
+	foo := []protocol.DocumentHighlight{
+		{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 10},
+				End:   protocol.Position{Line: 6, Character: 13},
+			},
+			Kind: &write,
+		},
+		{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 11, Character: 20},
+				End:   protocol.Position{Line: 11, Character: 23},
+			},
+			Kind: &read,
+		},
+		{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 11, Character: 34},
+				End:   protocol.Position{Line: 11, Character: 37},
+			},
+			Kind: &read,
+		},
+	}
+
+	tcs := []struct {
+		pos  protocol.Position
+		want []protocol.DocumentHighlight
+	}{
+		{
+			// definition
+			pos:  protocol.Position{Line: 6, Character: 11},
+			want: foo,
+		},
+		{
+			// reference
+			pos:  protocol.Position{Line: 11, Character: 35},
+			want: foo,
+		},
+		{
+			// builtin, without a definition
+			pos: protocol.Position{Line: 11, Character: 50},
+			want: []protocol.DocumentHighlight{
+				{
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 11, Character: 49},
+						End:   protocol.Position{Line: 11, Character: 58},
+					},
+					Kind: &read,
+				},
+			},
+		},
+		{
+			pos: protocol.Position{Line: 1, Character: 2},
+		},
+	}
+	for _, tc := range tcs {
+		got := f.DocumentHighlight(tc.pos)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("DocumentHighlight(%v):\ngot  %v\nwant %v", tc.pos, got, tc.want)
+		}
+	}
+}
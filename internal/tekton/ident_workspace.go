@@ -8,7 +8,7 @@ type IdentWorkspace StringMap
 
 var _ Meta = IdentWorkspace{}
 
-func (p IdentWorkspace) Completions() []completion {
+func (p IdentWorkspace) Completions(_ *Document) []completion {
 	return []completion{
 		{
 			text: fmt.Sprintf("$(workspaces.%s.path)", p.Name()),
@@ -25,6 +25,12 @@ func (p IdentWorkspace) Name() string {
 	return n
 }
 
+// Optional returns true if the workspace may be left unbound.
+func (p IdentWorkspace) Optional() bool {
+	o, _ := StringMap(p)["optional"].(bool)
+	return o
+}
+
 func (p IdentWorkspace) Description() string {
 	d, _ := StringMap(p)["description"].(string)
 	return d
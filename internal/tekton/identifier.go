@@ -18,6 +18,12 @@ const (
 	IdentKindWorkspace
 	IdentKindPipelineTask
 	IdentKindTask
+	IdentKindParamProperty
+	IdentKindContext
+	IdentKindStep
+	IdentKindSidecar
+	IdentKindStepResult
+	IdentKindPipeline
 )
 
 func (k identifierKind) String() string {
@@ -32,6 +38,18 @@ func (k identifierKind) String() string {
 		return "pipelineTask"
 	case IdentKindTask:
 		return "task"
+	case IdentKindParamProperty:
+		return "property"
+	case IdentKindContext:
+		return "context"
+	case IdentKindStep:
+		return "step"
+	case IdentKindSidecar:
+		return "sidecar"
+	case IdentKindStepResult:
+		return "stepResult"
+	case IdentKindPipeline:
+		return "pipeline"
 	}
 	return ""
 }
@@ -42,6 +60,15 @@ type identifier struct {
 	kind identifierKind
 	meta Meta
 
+	// parentKind and parentName are the lowercase kind and the name of the
+	// Tekton resource declaring this identifier.
+	parentKind string
+	parentName string
+
+	// builtin is true for identifiers provided by Tekton itself, which have
+	// no definition in the workspace.
+	builtin bool
+
 	definition ast.Node
 
 	location   protocol.Location
@@ -63,6 +90,15 @@ func (l *kindNameLocator) matches(id *identifier) bool {
 	return id.kind == l.kind && id.meta.Name() == l.name
 }
 
+// kindLocator locates every identifier of a given kind.
+type kindLocator struct {
+	kind identifierKind
+}
+
+func (l *kindLocator) matches(id *identifier) bool {
+	return id.kind == l.kind
+}
+
 // taskParamLocator locates an identifier given a paramater name and the task
 // which defines it.
 type taskParamLocator struct {
@@ -71,23 +107,80 @@ type taskParamLocator struct {
 }
 
 func (l *taskParamLocator) matches(id *identifier) bool {
+	if id.kind != IdentKindParam || id.meta.Name() != l.name {
+		return false
+	}
+	return (&taskParamsLocator{l.taskName}).matches(id)
+}
+
+// taskParamsLocator locates every parameter of a given task.
+type taskParamsLocator struct {
+	taskName string
+}
+
+func (l *taskParamsLocator) matches(id *identifier) bool {
 	if id.kind != IdentKindParam {
 		return false
 	}
-	if id.meta.Name() != l.name {
+	return id.parentKind == "task" && id.parentName == l.taskName
+}
+
+// taskWorkspaceLocator locates an identifier given a workspace name and the
+// task which declares it.
+type taskWorkspaceLocator struct {
+	name     string
+	taskName string
+}
+
+func (l *taskWorkspaceLocator) matches(id *identifier) bool {
+	if id.kind != IdentKindWorkspace || id.meta.Name() != l.name {
 		return false
 	}
-	p, ok := id.meta.(*identParam)
-	if !ok {
+	return (&taskWorkspacesLocator{l.taskName}).matches(id)
+}
+
+// taskWorkspacesLocator locates every workspace declared by a given task.
+type taskWorkspacesLocator struct {
+	taskName string
+}
+
+func (l *taskWorkspacesLocator) matches(id *identifier) bool {
+	if id.kind != IdentKindWorkspace {
 		return false
 	}
-	if p.parentKind != "task" {
+	return id.parentKind == "task" && id.parentName == l.taskName
+}
+
+// taskResultLocator locates an identifier given a result name and the task
+// which declares it.
+type taskResultLocator struct {
+	name     string
+	taskName string
+}
+
+func (l *taskResultLocator) matches(id *identifier) bool {
+	if id.kind != IdentKindResult || id.meta.Name() != l.name {
 		return false
 	}
-	if p.parentName != l.taskName {
+	return id.parentKind == "task" && id.parentName == l.taskName
+}
+
+// paramPropertyLocator locates an object parameter property given its key
+// and the name of the parameter which declares it.
+type paramPropertyLocator struct {
+	param string
+	key   string
+}
+
+func (l *paramPropertyLocator) matches(id *identifier) bool {
+	if id.kind != IdentKindParamProperty {
+		return false
+	}
+	p, ok := id.meta.(*paramProperty)
+	if !ok {
 		return false
 	}
-	return true
+	return p.param.Name() == l.param && p.key == l.key
 }
 
 func (d *Document) getIdent(l identLocator) *identifier {
@@ -97,9 +190,32 @@ func (d *Document) getIdent(l identLocator) *identifier {
 		}
 		return id
 	}
+	for _, id := range d.builtins {
+		if !l.matches(id) {
+			continue
+		}
+		return id
+	}
 	return nil
 }
 
+// getIdents returns every identifier in the document matched by the given
+// locator.
+func (d *Document) getIdents(l identLocator) []*identifier {
+	var ids []*identifier
+	for _, id := range d.identifiers {
+		if l.matches(id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range d.builtins {
+		if l.matches(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // identifiers is the list of rules used to find an identifier in a given
 // YAML document.
 var identifiers = []struct {
@@ -114,6 +230,11 @@ var identifiers = []struct {
 	// given the list of nodes matched by `paths`. Check `yaml.VisitPath` for
 	// more information.
 	meta func([]yaml_helper.ParsedNode) Meta
+
+	// keys, if set, makes the rule define one identifier for each key of the
+	// mapping matched by `paths`. The key node is appended to the list of
+	// nodes given to `meta`.
+	keys bool
 }{
 	{
 		kind: IdentKindParam,
@@ -125,6 +246,25 @@ var identifiers = []struct {
 			return IdentParameter(nodes[1].Value.(StringMap), nodes[0].Value)
 		},
 	},
+	{
+		kind: IdentKindParamProperty,
+		paths: []*yaml.Path{
+			mustPathString("$.spec.params[*]"),
+			mustPathString("$.properties"),
+		},
+		keys: true,
+		meta: func(nodes []yaml_helper.ParsedNode) Meta {
+			key, ok := nodes[3].Value.(string)
+			if !ok {
+				return nil
+			}
+			param := IdentParameter(nodes[1].Value.(StringMap), nodes[0].Value)
+			if param.Type() != "object" {
+				return nil
+			}
+			return ParamProperty(param, key)
+		},
+	},
 	{
 		kind: IdentKindResult,
 		paths: []*yaml.Path{
@@ -132,6 +272,9 @@ var identifiers = []struct {
 			mustPathString("$.name"),
 		},
 		meta: func(nodes []yaml_helper.ParsedNode) Meta {
+			if kind, _ := resourceKindName(nodes[0].Value); kind == "pipeline" {
+				return IdentPipelineResult(nodes[1].Value.(StringMap))
+			}
 			return IdentResult(nodes[1].Value.(StringMap))
 		},
 	},
@@ -152,7 +295,7 @@ var identifiers = []struct {
 			mustPathString("$.name"),
 		},
 		meta: func(nodes []yaml_helper.ParsedNode) Meta {
-			return PipelineTask(nodes[1].Value.(StringMap))
+			return PipelineTask(nodes[1].Value.(StringMap), nodes[1].Node)
 		},
 	},
 	{
@@ -168,6 +311,50 @@ var identifiers = []struct {
 			return IdentTask(nodes[0].Value.(StringMap))
 		},
 	},
+	{
+		kind: IdentKindPipeline,
+		paths: []*yaml.Path{
+			mustPathString("$.metadata.name"),
+		},
+		meta: func(nodes []yaml_helper.ParsedNode) Meta {
+			if kind, _ := resourceKindName(nodes[0].Value); kind != "pipeline" {
+				return nil
+			}
+			return IdentPipeline(nodes[0].Value.(StringMap))
+		},
+	},
+	{
+		kind: IdentKindStep,
+		paths: []*yaml.Path{
+			mustPathString("$.spec.steps[*]"),
+			mustPathString("$.name"),
+		},
+		meta: func(nodes []yaml_helper.ParsedNode) Meta {
+			return IdentStep(nodes[1].Value.(StringMap), nodes[1].Node)
+		},
+	},
+	{
+		kind: IdentKindSidecar,
+		paths: []*yaml.Path{
+			mustPathString("$.spec.sidecars[*]"),
+			mustPathString("$.name"),
+		},
+		meta: func(nodes []yaml_helper.ParsedNode) Meta {
+			return IdentStep(nodes[1].Value.(StringMap), nodes[1].Node)
+		},
+	},
+	{
+		kind: IdentKindStepResult,
+		paths: []*yaml.Path{
+			mustPathString("$.spec.steps[*]"),
+			mustPathString("$.results[*]"),
+			mustPathString("$.name"),
+		},
+		meta: func(nodes []yaml_helper.ParsedNode) Meta {
+			step, _ := nodes[1].Value.(StringMap)["name"].(string)
+			return StepResult(nodes[2].Value.(StringMap), step)
+		},
+	},
 }
 
 // getNodeRange returns the text document Range (start, end) and offsets (as
@@ -192,25 +379,50 @@ func (d *Document) getNodeRange(node ast.Node) (r protocol.Range, offsets []int)
 // yet.
 func (d *Document) parseIdentifiers() {
 	d.identifiers = d.identifiers[:0]
+	d.builtins = d.builtins[:0]
 	for _, ident := range identifiers {
 		yaml_helper.VisitPath(d.ast.Body, ident.paths, func(nodes []yaml_helper.ParsedNode) {
-			meta := ident.meta(nodes)
-			if meta == nil {
+			if !ident.keys {
+				d.addIdentifier(ident.kind, ident.meta, nodes)
 				return
 			}
-
-			def := nodes[len(nodes)-1]
-			defRange, _ := d.getNodeRange(def.Node)
-			id := &identifier{
-				kind:       ident.kind,
-				meta:       meta,
-				definition: def.Node,
-				location: protocol.Location{
-					Range: defRange,
-					URI:   d.file.uri,
-				},
+			for _, mv := range yaml_helper.MappingValues(nodes[len(nodes)-1].Node) {
+				key := yaml_helper.ParsedNode{
+					Node:  mv.Key,
+					Value: mv.Key.GetToken().Value,
+				}
+				d.addIdentifier(ident.kind, ident.meta, append(nodes, key))
 			}
-			d.identifiers = append(d.identifiers, id)
 		})
 	}
+	d.addContextIdentifiers()
+}
+
+// addIdentifier defines an identifier of the given kind whose definition is
+// the last node in the list, if the meta handler accepts the nodes.
+func (d *Document) addIdentifier(
+	kind identifierKind,
+	metaFn func([]yaml_helper.ParsedNode) Meta,
+	nodes []yaml_helper.ParsedNode,
+) {
+	meta := metaFn(nodes)
+	if meta == nil {
+		return
+	}
+
+	def := nodes[len(nodes)-1]
+	defRange, _ := d.getNodeRange(def.Node)
+	parentKind, parentName := resourceKindName(nodes[0].Value)
+	id := &identifier{
+		kind:       kind,
+		meta:       meta,
+		parentKind: parentKind,
+		parentName: parentName,
+		definition: def.Node,
+		location: protocol.Location{
+			Range: defRange,
+			URI:   d.file.uri,
+		},
+	}
+	d.identifiers = append(d.identifiers, id)
 }
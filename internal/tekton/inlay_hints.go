@@ -0,0 +1,102 @@
+package tekton
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// HintKind is the kind of a Hint.
+type HintKind int
+
+const (
+	// HintKindType is the type, and default if any, of a parameter.
+	HintKindType HintKind = iota + 1
+	// HintKindFile is the file declaring a referenced Task.
+	HintKindFile
+)
+
+// Hint is a label shown inline in the document, after Position.
+type Hint struct {
+	Position protocol.Position
+	Label    string
+	Kind     HintKind
+}
+
+// inlayHints returns the hints of the document: the type and default of
+// the parameters referred to by variable substitutions, and the file
+// declaring the Task referred to by each pipeline task.
+func (d *Document) inlayHints(r protocol.Range) []Hint {
+	var res []Hint
+	for _, ref := range d.references {
+		// hints are placed after the reference, which may end the range
+		if ref.ident == nil || (!inRange(ref.end, r) && ref.end != r.End) {
+			continue
+		}
+
+		switch m := ref.ident.meta.(type) {
+		case *identParam:
+			if !ref.substitution {
+				continue
+			}
+			label := ": " + m.Type()
+			if m.HasDefault() {
+				def, err := json.Marshal(m.value.(StringMap)["default"])
+				if err != nil {
+					continue
+				}
+				label = fmt.Sprintf("%s = %s", label, def)
+			}
+			res = append(res, Hint{
+				Position: ref.end,
+				Label:    label,
+				Kind:     HintKindType,
+			})
+
+		case IdentTask:
+			uri := ref.ident.location.URI
+			if uri == d.file.uri {
+				continue
+			}
+			res = append(res, Hint{
+				Position: ref.end,
+				Label:    relativeURI(d.file.uri, uri),
+				Kind:     HintKindFile,
+			})
+		}
+	}
+	return res
+}
+
+// relativeURI returns the path of the file target relative to the directory
+// of the file base, or the target URI if they are not both local files.
+func relativeURI(base string, target string) string {
+	b, ok := strings.CutPrefix(base, "file://")
+	if !ok {
+		return target
+	}
+	t, ok := strings.CutPrefix(target, "file://")
+	if !ok {
+		return target
+	}
+	rel, err := filepath.Rel(filepath.Dir(b), t)
+	if err != nil {
+		return target
+	}
+	return rel
+}
+
+// InlayHints returns the inlay hints of the file within the given range.
+func (f *File) InlayHints(r protocol.Range) []Hint {
+	res := []Hint{}
+	if f.parseError != nil {
+		return res
+	}
+	for _, d := range f.docs {
+		res = append(res, d.inlayHints(r)...)
+	}
+	return res
+}
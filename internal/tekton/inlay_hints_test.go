@@ -0,0 +1,107 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestInlayHints(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file:///ws/tasks/build.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: revision
+    default: main
+  - name: args
+    type: array
+    default: [-v]
+  - name: context
+  steps:
+  - name: build
+    image: busybox
+    args: ["$(params.args[*])"]
+    script: echo $(params.revision) $(params.context) $(params.unknown)
+`)
+	w.UpsertFile("file:///ws/pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    params:
+    - name: context
+      value: .
+`)
+	w.Lint()
+
+	everything := protocol.Range{
+		Start: protocol.Position{Line: 0, Character: 0},
+		End:   protocol.Position{Line: 100, Character: 0},
+	}
+	tcs := []struct {
+		uri  string
+		r    protocol.Range
+		want []Hint
+	}{
+		{
+			uri: "file:///ws/tasks/build.yaml",
+			r:   everything,
+			want: []Hint{
+				{
+					Position: protocol.Position{Line: 15, Character: 29},
+					Label:    `: array = ["-v"]`,
+					Kind:     HintKindType,
+				},
+				{
+					Position: protocol.Position{Line: 16, Character: 35},
+					Label:    `: string = "main"`,
+					Kind:     HintKindType,
+				},
+				{
+					Position: protocol.Position{Line: 16, Character: 53},
+					Label:    ": string",
+					Kind:     HintKindType,
+				},
+			},
+		},
+		{
+			uri: "file:///ws/tasks/build.yaml",
+			r: protocol.Range{
+				Start: protocol.Position{Line: 16, Character: 0},
+				End:   protocol.Position{Line: 16, Character: 35},
+			},
+			want: []Hint{
+				{
+					Position: protocol.Position{Line: 16, Character: 35},
+					Label:    `: string = "main"`,
+					Kind:     HintKindType,
+				},
+			},
+		},
+		{
+			uri: "file:///ws/pipe.yaml",
+			r:   everything,
+			want: []Hint{
+				{
+					Position: protocol.Position{Line: 8, Character: 17},
+					Label:    "tasks/build.yaml",
+					Kind:     HintKindFile,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		got := w.File(tc.uri).InlayHints(tc.r)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("InlayHints(%s, %v):\ngot  %+v\nwant %+v", tc.uri, tc.r, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package tekton
+
+import protocol "github.com/tliron/glsp/protocol_3_16"
+
+// linkedEditingRanges returns the ranges of the name of the identifier
+// defined or referred to in the given position, at its definition and
+// references, which can be edited simultaneously. Identifiers referred to
+// from other files are left to rename, since editing only this file would
+// break their references.
+func (d *Document) linkedEditingRanges(pos protocol.Position) []protocol.Range {
+	id := d.identifierAt(pos)
+	if id == nil || id.builtin || id.location.URI != d.file.uri {
+		return nil
+	}
+
+	res := []protocol.Range{id.location.Range}
+	for _, ref := range id.references {
+		if ref[1].URI != d.file.uri {
+			return nil
+		}
+		res = append(res, ref[1].Range)
+	}
+	if len(res) < 2 {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,95 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestLinkedEditingRanges(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  params:
+  - name: foo
+  - name: unused
+  steps:
+  - name: echo
+    image: busybox
+    script: |
+      echo $(params.foo) $(params.foo) $(context.task.name)
+`))
+
+	foo := []protocol.Range{
+		{
+			Start: protocol.Position{Line: 6, Character: 10},
+			End:   protocol.Position{Line: 6, Character: 13},
+		},
+		{
+			Start: protocol.Position{Line: 12, Character: 20},
+			End:   protocol.Position{Line: 12, Character: 23},
+		},
+		{
+			Start: protocol.Position{Line: 12, Character: 34},
+			End:   protocol.Position{Line: 12, Character: 37},
+		},
+	}
+
+	tcs := []struct {
+		name string
+		pos  protocol.Position
+		want []protocol.Range
+	}{
+		{"definition", protocol.Position{Line: 6, Character: 11}, foo},
+		{"reference", protocol.Position{Line: 12, Character: 35}, foo},
+		{"unreferenced", protocol.Position{Line: 7, Character: 11}, nil},
+		{"builtin", protocol.Position{Line: 12, Character: 50}, nil},
+		{"no identifier", protocol.Position{Line: 1, Character: 2}, nil},
+	}
+	for _, tc := range tcs {
+		got := f.LinkedEditingRanges(tc.pos)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: LinkedEditingRanges(%v):\ngot  %v\nwant %v", tc.name, tc.pos, got, tc.want)
+		}
+	}
+}
+
+func TestLinkedEditingRangesCrossFile(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: foo
+  steps:
+  - name: echo
+    image: busybox
+    script: echo $(params.foo)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    params:
+    - name: foo
+      value: bar
+`)
+	w.Lint()
+
+	// foo is passed by the pipeline, and must be renamed instead
+	task := w.File("file://task.yaml")
+	if got := task.LinkedEditingRanges(protocol.Position{Line: 6, Character: 11}); got != nil {
+		t.Errorf("LinkedEditingRanges: got %v, want nil", got)
+	}
+}
@@ -0,0 +1,223 @@
+package tekton
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// resolverRefPaths is the list of paths, as in yaml_helper.VisitPath, of
+// the taskRef and pipelineRef objects which may use a remote resolver.
+var resolverRefPaths = [][]*yaml.Path{
+	{mustPathString("$.spec.tasks[*]"), mustPathString("$.taskRef")},
+	{mustPathString("$.spec.finally[*]"), mustPathString("$.taskRef")},
+	{mustPathString("$.spec.pipelineSpec.tasks[*]"), mustPathString("$.taskRef")},
+	{mustPathString("$.spec.pipelineSpec.finally[*]"), mustPathString("$.taskRef")},
+	{mustPathString("$.spec.taskRef")},
+	{mustPathString("$.spec.pipelineRef")},
+}
+
+// documentLinks returns the links of the document: Task and Pipeline
+// references to the files declaring them, git resolver parameters to the
+// file they fetch if the repository is a workspace folder, and container
+// images to their registry page.
+func (d *Document) documentLinks() []protocol.DocumentLink {
+	var res []protocol.DocumentLink
+	add := func(r protocol.Range, target string) {
+		res = append(res, protocol.DocumentLink{
+			Range:  r,
+			Target: &target,
+		})
+	}
+
+	for _, ref := range d.references {
+		if ref.kind != IdentKindTask && ref.kind != IdentKindPipeline {
+			continue
+		}
+		if ref.ident == nil || ref.ident.builtin {
+			continue
+		}
+		if uri := ref.ident.location.URI; uri != d.file.uri {
+			add(protocol.Range{Start: ref.start, End: ref.end}, uri)
+		}
+	}
+
+	for _, paths := range resolverRefPaths {
+		yaml_helper.VisitPath(d.ast.Body, paths, func(nodes []yaml_helper.ParsedNode) {
+			for _, l := range d.gitResolverLinks(nodes[len(nodes)-1]) {
+				add(l.Range, *l.Target)
+			}
+		})
+	}
+
+	ast.Walk(yaml_helper.VisitorFunc(func(n ast.Node) bool {
+		mv, ok := n.(*ast.MappingValueNode)
+		if !ok || mv.Key.GetToken().Value != "image" {
+			return true
+		}
+		v, ok := mv.Value.(*ast.StringNode)
+		if !ok {
+			return true
+		}
+		if url := imageURL(v.Value); url != "" {
+			r, _ := d.getNodeRange(v)
+			add(r, url)
+		}
+		return true
+	}), d.ast.Body)
+
+	return res
+}
+
+// gitResolverLinks returns links from the `url` and `pathInRepo` params of
+// a taskRef or pipelineRef using the git resolver to the file it fetches,
+// when the repository is checked out as a workspace folder.
+func (d *Document) gitResolverLinks(ref yaml_helper.ParsedNode) []protocol.DocumentLink {
+	rm, ok := ref.Value.(map[string]interface{})
+	if !ok || rm["resolver"] != "git" {
+		return nil
+	}
+
+	values := map[string]ast.Node{}
+	yaml_helper.VisitPath(ref.Node, []*yaml.Path{mustPathString("$.params[*]")}, func(nodes []yaml_helper.ParsedNode) {
+		pm, ok := nodes[1].Value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		name, _ := pm["name"].(string)
+		node, err := mustPathString("$.value").FilterNode(nodes[1].Node)
+		if err != nil || node == nil {
+			return
+		}
+		values[name] = node
+	})
+
+	repo, path := values["url"], values["pathInRepo"]
+	if repo == nil || path == nil {
+		return nil
+	}
+	folder := d.file.workspace.repositoryFolder(repo.GetToken().Value)
+	if folder == "" {
+		return nil
+	}
+	file := filepath.Join(folder, filepath.FromSlash(path.GetToken().Value))
+	if _, err := os.Stat(file); err != nil {
+		return nil
+	}
+	target := fileURI(file)
+
+	var res []protocol.DocumentLink
+	for _, node := range []ast.Node{repo, path} {
+		r, _ := d.getNodeRange(node)
+		res = append(res, protocol.DocumentLink{
+			Range:  r,
+			Target: &target,
+		})
+	}
+	return res
+}
+
+// fileURI returns the file URI of the given absolute path.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows drive letters, as in file:///C:/path
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// repositoryFolder returns the path of the workspace folder which is a
+// checkout of the git repository with the given URL, or "" if there is none.
+func (w *Workspace) repositoryFolder(url string) string {
+	want := normalizeGitURL(url)
+	for _, folder := range w.folders {
+		f, err := os.Open(filepath.Join(folder, ".git", "config"))
+		if err != nil {
+			continue
+		}
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			key, value, ok := strings.Cut(s.Text(), "=")
+			if ok && strings.TrimSpace(key) == "url" && normalizeGitURL(strings.TrimSpace(value)) == want {
+				f.Close()
+				return folder
+			}
+		}
+		f.Close()
+	}
+	return ""
+}
+
+// normalizeGitURL returns the host and path of a git remote URL, so that
+// the HTTPS and SSH URLs of a repository are equal, as in
+// `github.com/org/repo`.
+func normalizeGitURL(url string) string {
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		url = rest
+	} else if host, path, ok := strings.Cut(url, ":"); ok {
+		// scp-like syntax, as in git@github.com:org/repo.git
+		url = host + "/" + path
+	}
+	if _, rest, ok := strings.Cut(url, "@"); ok {
+		url = rest
+	}
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	return strings.ToLower(url)
+}
+
+// imageURL returns the URL of the registry page of a container image, or ""
+// if it's unknown.
+func imageURL(image string) string {
+	if image == "" || strings.Contains(image, "$(") {
+		return ""
+	}
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	host, path, ok := strings.Cut(image, "/")
+	if !ok || !strings.ContainsAny(host, ".:") && host != "localhost" {
+		// Docker Hub
+		host, path = "docker.io", image
+	}
+
+	switch host {
+	case "docker.io", "index.docker.io", "registry-1.docker.io":
+		if name, ok := strings.CutPrefix(path, "library/"); ok {
+			path = name
+		}
+		if !strings.Contains(path, "/") {
+			return "https://hub.docker.com/_/" + path
+		}
+		return "https://hub.docker.com/r/" + path
+	case "quay.io":
+		return "https://quay.io/repository/" + path
+	case "localhost":
+		return ""
+	}
+	if strings.HasPrefix(host, "localhost:") {
+		return ""
+	}
+	return "https://" + host + "/" + path
+}
+
+// DocumentLinks returns the links of the file.
+func (f *File) DocumentLinks() []protocol.DocumentLink {
+	res := []protocol.DocumentLink{}
+	if f.parseError != nil {
+		return res
+	}
+	for _, d := range f.docs {
+		res = append(res, d.documentLinks()...)
+	}
+	return res
+}
@@ -0,0 +1,153 @@
+package tekton
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestImageURL(t *testing.T) {
+	tcs := map[string]string{
+		"busybox":                         "https://hub.docker.com/_/busybox",
+		"busybox:1.36":                    "https://hub.docker.com/_/busybox",
+		"library/alpine@sha256:abcd":      "https://hub.docker.com/_/alpine",
+		"bitnami/kubectl:latest":          "https://hub.docker.com/r/bitnami/kubectl",
+		"docker.io/library/golang:1.22":   "https://hub.docker.com/_/golang",
+		"quay.io/buildah/stable:v1":       "https://quay.io/repository/buildah/stable",
+		"gcr.io/distroless/static":        "https://gcr.io/distroless/static",
+		"ghcr.io/org/image:v1":            "https://ghcr.io/org/image",
+		"localhost:5000/image":            "",
+		"$(params.image)":                 "",
+		"registry.example.com:443/a/b:v2": "https://registry.example.com:443/a/b",
+	}
+	for image, want := range tcs {
+		if got := imageURL(image); got != want {
+			t.Errorf("imageURL(%q): got %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestNormalizeGitURL(t *testing.T) {
+	want := "github.com/org/repo"
+	for _, url := range []string{
+		"https://github.com/org/repo",
+		"https://github.com/org/repo.git",
+		"git@github.com:org/repo.git",
+		"ssh://git@github.com/org/repo",
+		"https://github.com/Org/Repo/",
+	} {
+		if got := normalizeGitURL(url); got != want {
+			t.Errorf("normalizeGitURL(%q): got %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestFileURI(t *testing.T) {
+	tcs := map[string]string{
+		"/home/user/task.yaml":         "file:///home/user/task.yaml",
+		"/home/user/my tasks/a#b.yaml": "file:///home/user/my%20tasks/a%23b.yaml",
+	}
+	for path, want := range tcs {
+		if got := fileURI(filepath.FromSlash(path)); got != want {
+			t.Errorf("fileURI(%q): got %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDocumentLinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gitConfig := "[remote \"origin\"]\n\turl = git@github.com:org/repo.git\n"
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte(gitConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	taskPath := filepath.Join(dir, "task.yaml")
+	task := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  steps:
+  - name: echo
+    image: busybox
+`
+	if err := os.WriteFile(taskPath, []byte(task), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWorkspace()
+	w.AddFolder("file://" + dir)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: local
+    taskRef:
+      name: build
+  - name: remote
+    taskRef:
+      resolver: git
+      params:
+      - name: url
+        value: https://github.com/org/repo
+      - name: pathInRepo
+        value: task.yaml
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: run
+spec:
+  pipelineRef:
+    name: pipeline
+`)
+	w.UpsertFile("file://other.yaml", `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: run
+spec:
+  pipelineRef:
+    name: pipeline
+`)
+	w.Lint()
+
+	link := func(sl, sc, ec uint32, target string) protocol.DocumentLink {
+		return protocol.DocumentLink{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: sl, Character: sc},
+				End:   protocol.Position{Line: sl, Character: ec},
+			},
+			Target: &target,
+		}
+	}
+
+	taskURI := "file://" + taskPath
+	got := w.File("file://pipe.yaml").DocumentLinks()
+	want := []protocol.DocumentLink{
+		link(8, 12, 17, taskURI),
+		link(14, 15, 42, fileURI(taskPath)),
+		link(16, 15, 24, fileURI(taskPath)),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DocumentLinks(pipe.yaml):\ngot  %v\nwant %v", got, want)
+	}
+
+	got = w.File("file://other.yaml").DocumentLinks()
+	want = []protocol.DocumentLink{link(6, 10, 18, "file://pipe.yaml")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DocumentLinks(other.yaml):\ngot  %v\nwant %v", got, want)
+	}
+
+	got = w.File(taskURI).DocumentLinks()
+	want = []protocol.DocumentLink{link(7, 11, 18, "https://hub.docker.com/_/busybox")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DocumentLinks(task.yaml):\ngot  %v\nwant %v", got, want)
+	}
+}
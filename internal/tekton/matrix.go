@@ -0,0 +1,111 @@
+package tekton
+
+import (
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// matrix returns the `matrix` of the pipeline task, or nil if it doesn't
+// fan out.
+func (p *pipelineTask) matrix() map[string]interface{} {
+	m, _ := p.value["matrix"].(map[string]interface{})
+	return m
+}
+
+// isArrayValue returns true if v is a YAML sequence or a string made of a
+// single substitution expanding a whole array, such as $(params.name[*]).
+func isArrayValue(v interface{}) bool {
+	switch t := v.(type) {
+	case []interface{}:
+		return true
+	case string:
+		subs := parseSubstitutions([]byte(t), 0)
+		if len(subs) != 1 || subs[0].start != 0 || subs[0].end != len(t) {
+			return false
+		}
+		segs := subs[0].segments
+		last := segs[len(segs)-1]
+		return last.index && last.value == "*"
+	}
+	return false
+}
+
+// matrixDiagnostics reports matrix parameters which are not arrays or are
+// also passed in `params`, and results of matrixed pipeline tasks which are
+// consumed as strings.
+func (d *Document) matrixDiagnostics(c chan<- *protocol.Diagnostic) {
+	matrixed := map[string]struct{}{}
+	for _, id := range d.identifiers {
+		pt, ok := id.meta.(*pipelineTask)
+		if !ok || pt.matrix() == nil || pt.node == nil {
+			continue
+		}
+		matrixed[pt.Name()] = struct{}{}
+
+		params := map[string]struct{}{}
+		addNames(params, pt.value["params"])
+
+		paths := []*yaml.Path{mustPathString("$.matrix.params[*]")}
+		yaml_helper.VisitPath(pt.node, paths, func(nodes []yaml_helper.ParsedNode) {
+			pm, ok := nodes[1].Value.(map[string]interface{})
+			if !ok {
+				return
+			}
+			name, _ := pm["name"].(string)
+			nameNode, err := mustPathString("$.name").FilterNode(nodes[1].Node)
+			if err != nil || nameNode == nil {
+				return
+			}
+			r, _ := d.getNodeRange(nameNode)
+
+			if _, ok := params[name]; ok {
+				c <- newDiagnostic(
+					r,
+					protocol.DiagnosticSeverityError,
+					"matrix-parameter",
+					"parameter %s is passed both in params and matrix",
+					name,
+				)
+			}
+			if v, ok := pm["value"]; ok && !isArrayValue(v) {
+				c <- newDiagnostic(
+					r,
+					protocol.DiagnosticSeverityError,
+					"matrix-parameter",
+					"matrix parameter %s must be an array",
+					name,
+				)
+			}
+		})
+	}
+
+	if len(matrixed) == 0 {
+		return
+	}
+	for _, s := range d.substitutions {
+		names := s.names()
+		if len(names) < 4 || names[0].value != "tasks" || names[2].value != "results" {
+			continue
+		}
+		if _, ok := matrixed[names[1].value]; !ok {
+			continue
+		}
+		if len(names) < len(s.segments) {
+			// indexed or expanded with [*]
+			continue
+		}
+		c <- newDiagnostic(
+			protocol.Range{
+				Start: d.OffsetPosition(s.start),
+				End:   d.OffsetPosition(s.end),
+			},
+			protocol.DiagnosticSeverityError,
+			"matrix-result",
+			"results of matrixed task %s are arrays, use $(tasks.%s.results.%s[*])",
+			names[1].value,
+			names[1].value,
+			names[3].value,
+		)
+	}
+}
@@ -0,0 +1,88 @@
+package tekton
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: platform
+  - name: version
+  - name: flags
+    default: ""
+  results:
+  - name: digest
+  steps:
+  - name: build
+    image: busybox
+    script: |
+      echo $(params.platform) $(params.version) $(params.flags) > $(results.digest.path)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  params:
+  - name: versions
+    type: array
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    params:
+    - name: flags
+      value: -v
+    matrix:
+      params:
+      - name: platform
+        value: [linux, mac]
+      - name: version
+        value: $(params.versions[*])
+      - name: flags
+        value: -x
+      include:
+      - name: arm
+        params:
+        - name: unknown
+          value: arm
+  - name: push
+    taskRef:
+      name: build
+    params:
+    - name: platform
+      value: $(tasks.build.results.digest)
+    - name: version
+      value: $(tasks.build.results.digest[*])
+`)
+	w.Lint()
+
+	got := diagnosticMessages(w.File("file://pipe.yaml"))
+	slices.Sort(got)
+	want := []string{
+		"matrix parameter flags must be an array",
+		"parameter flags is passed both in params and matrix",
+		"results of matrixed task build are arrays, use $(tasks.build.results.digest[*])",
+		"unknown parameter unknown",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	id := w.getIdent(&taskParamLocator{name: "platform", taskName: "build"})
+	if id == nil {
+		t.Fatalf("param platform not found")
+	}
+	// the script substitution, the matrix param and the push task param
+	if got := len(id.references); got != 3 {
+		t.Errorf("references of platform: got %d, want 3", got)
+	}
+}
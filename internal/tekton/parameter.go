@@ -1,69 +1,97 @@
 package tekton
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
 
 type identParam struct {
-	value      interface{}
-	parent     interface{}
-	parentKind string
-	parentName string
+	value  interface{}
+	parent interface{}
 }
 
 func IdentParameter(v StringMap, parent interface{}) *identParam {
-	p := &identParam{
+	return &identParam{
 		value:  v,
 		parent: parent,
 	}
+}
 
-	pm := parent.(map[string]interface{})
-	kind, ok := pm["kind"].(string)
-	if ok {
-		p.parentKind = strings.ToLower(kind)
+// resourceKindName returns the lowercase kind and the name of the given
+// Tekton resource.
+func resourceKindName(resource interface{}) (kind string, name string) {
+	rm, ok := resource.(map[string]interface{})
+	if !ok {
+		return "", ""
 	}
 
-	var meta map[string]interface{}
-	if m, ok := pm["metadata"]; ok {
-		meta, _ = m.(map[string]interface{})
+	if k, ok := rm["kind"].(string); ok {
+		kind = strings.ToLower(k)
 	}
 
-	if meta != nil {
-		p.parentName, _ = meta["name"].(string)
+	if meta, ok := rm["metadata"].(map[string]interface{}); ok {
+		name, _ = meta["name"].(string)
 	}
-
-	return p
+	return kind, name
 }
 
 var _ Meta = &identParam{}
 
-func (p *identParam) Completions() []completion {
+func (p *identParam) Completions(_ *Document) []completion {
 	cs := []completion{}
-	if p.Type() == "array" {
+	if p.Type() == "array" || p.Type() == "object" {
 		cs = append(cs,
 			completion{
-				text: fmt.Sprintf("$(params.%s[*])", p.Name()),
+				text: fmt.Sprintf("$(%s[*])", p.path()),
 			},
 		)
 	} else {
 		cs = append(cs,
 			completion{
-				text: fmt.Sprintf("$(params.%s)", p.Name()),
+				text: fmt.Sprintf("$(%s)", p.path()),
 			},
 		)
 	}
 	return cs
 }
 
+// path returns the substitution path of the parameter, using the bracket
+// notation for names containing dots.
+func (p *identParam) path() string {
+	if strings.Contains(p.Name(), ".") {
+		return fmt.Sprintf("params[%q]", p.Name())
+	}
+	return "params." + p.Name()
+}
+
 func (p *identParam) Name() string {
 	n, _ := StringMap(p.value.(map[string]interface{}))["name"].(string)
 	return n
 }
 
+// Default returns the default value of the parameter, marshalled as JSON
+// unless it's a string, or an empty string if it has none.
 func (p *identParam) Default() string {
-	d, _ := StringMap(p.value.(map[string]interface{}))["default"].(string)
-	return d
+	d, ok := StringMap(p.value.(map[string]interface{}))["default"]
+	if !ok {
+		return ""
+	}
+	if s, ok := d.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// HasDefault returns true if the parameter declares a default value of
+// any type.
+func (p *identParam) HasDefault() bool {
+	_, ok := StringMap(p.value.(map[string]interface{}))["default"]
+	return ok
 }
 
 func (p *identParam) Type() string {
@@ -73,6 +101,22 @@ func (p *identParam) Type() string {
 	return "string"
 }
 
+// Properties returns the keys declared by an object parameter, mapped to
+// their types.
+func (p *identParam) Properties() map[string]string {
+	props := map[string]string{}
+	pm, _ := StringMap(p.value.(map[string]interface{}))["properties"].(map[string]interface{})
+	for k, v := range pm {
+		props[k] = "string"
+		if vm, ok := v.(map[string]interface{}); ok {
+			if t, ok := vm["type"].(string); ok {
+				props[k] = t
+			}
+		}
+	}
+	return props
+}
+
 func (p *identParam) Description() string {
 	d, _ := StringMap(p.value.(map[string]interface{}))["description"].(string)
 	return d
@@ -87,3 +131,45 @@ func (p *identParam) Documentation() string {
 		p.Description(),
 	)
 }
+
+// paramProperty is a key declared in the `properties` of an object parameter.
+type paramProperty struct {
+	param *identParam
+	key   string
+}
+
+func ParamProperty(param *identParam, key string) *paramProperty {
+	return &paramProperty{
+		param: param,
+		key:   key,
+	}
+}
+
+var _ Meta = &paramProperty{}
+
+func (p *paramProperty) Completions(_ *Document) []completion {
+	return []completion{
+		{
+			text: fmt.Sprintf("$(%s.%s)", p.param.path(), p.key),
+		},
+	}
+}
+
+func (p *paramProperty) Name() string {
+	return p.key
+}
+
+// Type returns the type of the property, as declared in the object
+// parameter `properties`.
+func (p *paramProperty) Type() string {
+	return p.param.Properties()[p.key]
+}
+
+func (p *paramProperty) Documentation() string {
+	return fmt.Sprintf(
+		"```yaml\nparam: %s\nkey: %s\ntype: %s\n```",
+		p.param.Name(),
+		p.key,
+		p.Type(),
+	)
+}
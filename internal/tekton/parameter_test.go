@@ -0,0 +1,116 @@
+package tekton
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+const objectParamDoc = `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: clone
+spec:
+  params:
+  - name: git
+    type: object
+    properties:
+      url: {}
+      commit:
+        type: string
+  steps:
+  - name: clone
+    image: alpine/git
+    script: |
+      git clone $(params.git.url) && git checkout $(params.git.commit)
+      echo $(params.git.url)
+`
+
+func TestObjectParamProperties(t *testing.T) {
+	f := parseFile(file.TextDocument(objectParamDoc))
+	d := f.docs[0]
+
+	urlRefs := []protocol.Range{
+		{
+			Start: protocol.Position{Line: 16, Character: 29},
+			End:   protocol.Position{Line: 16, Character: 32},
+		},
+		{
+			Start: protocol.Position{Line: 17, Character: 24},
+			End:   protocol.Position{Line: 17, Character: 27},
+		},
+	}
+
+	id := d.getIdent(&paramPropertyLocator{param: "git", key: "url"})
+	if id == nil {
+		t.Fatalf("property git.url not found")
+	}
+	if got := locationToRange(wholeReferences(id)); !reflect.DeepEqual(got, urlRefs) {
+		t.Errorf("references:\ngot %v\nwant %v", got, urlRefs)
+	}
+
+	// the parameter itself is referenced by every substitution
+	param := d.getIdent(&kindNameLocator{IdentKindParam, "git"})
+	if got := len(param.references); got != 3 {
+		t.Errorf("param references: got %d, want 3", got)
+	}
+
+	hover := f.Hover(protocol.Position{Line: 16, Character: 64})
+	if hover == nil || !strings.Contains(*hover, "key: commit") {
+		t.Errorf("Hover: got %v, want commit property documentation", hover)
+	}
+
+	edit, err := f.Rename(protocol.Position{Line: 9, Character: 7}, "repository")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	var got []protocol.Range
+	for _, e := range edit.Changes[f.uri] {
+		got = append(got, e.Range)
+	}
+	want := append([]protocol.Range{
+		{
+			Start: protocol.Position{Line: 9, Character: 6},
+			End:   protocol.Position{Line: 9, Character: 9},
+		},
+	}, urlRefs...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rename:\ngot %v\nwant %v", got, want)
+	}
+
+	var texts []string
+	for _, c := range d.completions(protocol.Position{Line: 17, Character: 11}) {
+		texts = append(texts, c.String())
+	}
+	for _, want := range []string{"$(params.git.url)", "$(params.git.commit)"} {
+		if !strings.Contains(strings.Join(texts, " "), want) {
+			t.Errorf("Completions: %q not found in %v", want, texts)
+		}
+	}
+}
+
+func TestTaskParamLocators(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: foo
+  - name: bar
+`)
+
+	if id := w.getIdent(&taskParamLocator{name: "", taskName: "build"}); id != nil {
+		t.Errorf("taskParamLocator: got %s for an empty name, want nil", id.meta.Name())
+	}
+	if id := w.getIdent(&taskParamLocator{name: "bar", taskName: "build"}); id == nil {
+		t.Errorf("taskParamLocator: param bar not found")
+	}
+	if got := len(w.getIdents(&taskParamsLocator{taskName: "build"})); got != 2 {
+		t.Errorf("taskParamsLocator: got %d params, want 2", got)
+	}
+}
@@ -0,0 +1,56 @@
+package tekton
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IdentPipeline is a Pipeline, referred to by name from the pipelineRef of
+// PipelineRuns.
+type IdentPipeline StringMap
+
+var _ Meta = IdentPipeline{}
+
+func (p IdentPipeline) Completions(_ *Document) []completion {
+	return []completion{}
+}
+
+func (p IdentPipeline) Name() string {
+	meta, ok := StringMap(p)["metadata"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	n, _ := meta["name"].(string)
+	return n
+}
+
+// Results returns the results declared in the Pipeline spec, which are the
+// results of its PipelineRuns.
+func (p IdentPipeline) Results() []IdentPipelineResult {
+	spec, _ := StringMap(p)["spec"].(map[string]interface{})
+	rs, _ := spec["results"].([]interface{})
+
+	var res []IdentPipelineResult
+	for _, r := range rs {
+		if rm, ok := r.(map[string]interface{}); ok {
+			res = append(res, IdentPipelineResult(rm))
+		}
+	}
+	return res
+}
+
+func (p IdentPipeline) Documentation() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "```yaml\nname: %s\n", p.Name())
+	if rs := p.Results(); len(rs) > 0 {
+		b.WriteString("results:\n")
+		for _, r := range rs {
+			fmt.Fprintf(&b, "- name: %s\n  type: %s\n", r.Name(), r.Type())
+			if v, ok := r.Value().(string); ok {
+				fmt.Fprintf(&b, "  value: %s\n", v)
+			}
+		}
+	}
+	b.WriteString("```")
+	return b.String()
+}
@@ -1,23 +1,160 @@
 package tekton
 
-type PipelineTask StringMap
+import (
+	"fmt"
 
-var _ Meta = PipelineTask{}
+	"github.com/goccy/go-yaml/ast"
+)
 
-func (p PipelineTask) Completions() []completion {
-	return []completion{
+type pipelineTask struct {
+	value StringMap
+
+	// node is the YAML node containing the whole pipeline task definition.
+	node ast.Node
+}
+
+func PipelineTask(v StringMap, node ast.Node) *pipelineTask {
+	return &pipelineTask{
+		value: v,
+		node:  node,
+	}
+}
+
+var _ Meta = &pipelineTask{}
+
+func (p *pipelineTask) Completions(d *Document) []completion {
+	cs := []completion{
 		{
 			text:    p.Name(),
 			context: mustPathString("$.spec.tasks[*].runAfter"),
 		},
 	}
+	for _, r := range p.results(d.file.workspace) {
+		cs = append(cs, completion{
+			text:  fmt.Sprintf("$(tasks.%s.results.%s)", p.Name(), r.Name()),
+			value: r,
+			kind:  IdentKindResult.completionItemKind(),
+		})
+	}
+
+	// suggest the referenced Task parameters which are not yet set
+	set := p.paramNames()
+	for _, id := range p.taskParams(d.file.workspace) {
+		if _, ok := set[id.meta.Name()]; ok {
+			continue
+		}
+		cs = append(cs, completion{
+			text:    id.meta.Name(),
+			context: mustPathString("$.params"),
+			scope:   p.node,
+			value:   id.meta,
+			kind:    id.kind.completionItemKind(),
+		})
+	}
+	return append(cs, p.whenCompletions()...)
 }
 
-func (p PipelineTask) Name() string {
-	n, _ := StringMap(p)["name"].(string)
+func (p *pipelineTask) Name() string {
+	n, _ := p.value["name"].(string)
+	return n
+}
+
+// TaskRef returns the name of the Task referenced by this PipelineTask
+// through `taskRef.name`, or an empty string if there is none.
+func (p *pipelineTask) TaskRef() string {
+	tr, ok := p.value["taskRef"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	n, _ := tr["name"].(string)
 	return n
 }
 
-func (p PipelineTask) Documentation() string {
+// paramNames returns the set of parameter names passed to the Task by this
+// PipelineTask, either through `params` or its `matrix`.
+func (p *pipelineTask) paramNames() map[string]struct{} {
+	names := map[string]struct{}{}
+	addNames(names, p.value["params"])
+	addNames(names, p.matrix()["params"])
+	include, _ := p.matrix()["include"].([]interface{})
+	for _, v := range include {
+		if im, ok := v.(map[string]interface{}); ok {
+			addNames(names, im["params"])
+		}
+	}
+	return names
+}
+
+// addNames adds to the given set the `name` of every mapping in list.
+func addNames(names map[string]struct{}, list interface{}) {
+	l, _ := list.([]interface{})
+	for _, v := range l {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if n, ok := m["name"].(string); ok {
+			names[n] = struct{}{}
+		}
+	}
+}
+
+// workspaceNames returns the set of Task workspace names bound by this
+// PipelineTask.
+func (p *pipelineTask) workspaceNames() map[string]struct{} {
+	names := map[string]struct{}{}
+	addNames(names, p.value["workspaces"])
+	return names
+}
+
+// taskWorkspaces returns the workspace identifiers declared by the Task
+// referenced by this PipelineTask.
+func (p *pipelineTask) taskWorkspaces(w *Workspace) []*identifier {
+	name := p.TaskRef()
+	if name == "" || w == nil {
+		return nil
+	}
+	return w.getIdents(&taskWorkspacesLocator{taskName: name})
+}
+
+// taskParams returns the parameter identifiers declared by the Task
+// referenced by this PipelineTask.
+func (p *pipelineTask) taskParams(w *Workspace) []*identifier {
+	name := p.TaskRef()
+	if name == "" || w == nil {
+		return nil
+	}
+	return w.getIdents(&taskParamsLocator{taskName: name})
+}
+
+// results returns the results declared by the Task this PipelineTask runs,
+// either embedded in its `taskSpec` or declared by the Task it references.
+func (p *pipelineTask) results(w *Workspace) []IdentResult {
+	if spec, ok := p.value["taskSpec"]; ok {
+		return specResults(spec)
+	}
+	task := p.task(w)
+	if task == nil {
+		return nil
+	}
+	return task.Results()
+}
+
+// task returns the Task referenced by this PipelineTask, or nil if it
+// can't be found in the workspace.
+func (p *pipelineTask) task(w *Workspace) IdentTask {
+	name := p.TaskRef()
+	if name == "" || w == nil {
+		return nil
+	}
+	id := w.getIdent(&kindNameLocator{IdentKindTask, name})
+	if id == nil {
+		return nil
+	}
+	t, _ := id.meta.(IdentTask)
+	return t
+}
+
+func (p *pipelineTask) Documentation() string {
 	return ""
 }
@@ -1,7 +1,7 @@
 package tekton
 
 import (
-	"regexp"
+	"strings"
 
 	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
 	"github.com/goccy/go-yaml"
@@ -18,6 +18,15 @@ type reference struct {
 	// name is the identifier name referred to by this reference.
 	name string
 
+	// substitution is true if this reference is a variable substitution,
+	// such as $(params.name), instead of a plain YAML value.
+	substitution bool
+
+	// accessor is the text following the identifier name in a variable
+	// substitution reference, such as `[*]` in $(params.name[*]) or `.path`
+	// in $(results.name.path).
+	accessor string
+
 	// docURI is the TextDocument URI in which this reference is found.
 	docURI string
 
@@ -49,57 +58,69 @@ type referenceResolver interface {
 	find(*Document)
 }
 
-// regexpRef implements referenceResolver given a regular expression.
-type regexpRef struct {
-	kind  identifierKind
-	regex *regexp.Regexp
+// substitutionRef implements referenceResolver given a function handler
+// which turns a variable substitution expression found in the document into
+// a list of references. The handler must set the reference offsets, from
+// which its positions are calculated.
+type substitutionRef struct {
+	handler func(*Document, substitution) []reference
 }
 
-var _ referenceResolver = &regexpRef{}
-
-// find implements referenceResolver by finding all matches of a regular
-// expression in a given document.
-func (r *regexpRef) find(d *Document) {
-	// this can be reused between documents
-	refs := r.regex.FindAllSubmatchIndex(d.Bytes(), 1000)
-	for _, match := range refs {
-		name := string(d.Bytes())[match[2]:match[3]]
-		id := d.getIdent(&kindNameLocator{r.kind, name})
+var _ referenceResolver = &substitutionRef{}
 
-		if match[0] < d.offset || match[1] > d.offset+d.size {
-			continue
+func (r *substitutionRef) find(d *Document) {
+	for _, s := range d.substitutions {
+		for _, ref := range r.handler(d, s) {
+			ref.docURI = d.file.uri
+			ref.substitution = true
+			ref.start = d.OffsetPosition(ref.offsets[0])
+			ref.end = d.OffsetPosition(ref.offsets[1])
+			if id := ref.ident; id != nil {
+				id.references = append(id.references, []protocol.Location{
+					{
+						URI: d.file.uri,
+						Range: protocol.Range{
+							Start: ref.start,
+							End:   ref.end,
+						},
+					},
+					{
+						URI: d.file.uri,
+						Range: protocol.Range{
+							Start: d.OffsetPosition(ref.offsets[2]),
+							End:   d.OffsetPosition(ref.offsets[3]),
+						},
+					},
+				})
+			} else {
+				d.file.danglingRefs[ref.name] = struct{}{}
+			}
+			d.references = append(d.references, ref)
 		}
+	}
+}
 
-		start := d.OffsetPosition(match[0])
-		end := d.OffsetPosition(match[1])
-		if id != nil {
-			id.references = append(id.references, []protocol.Location{
-				{
-					URI: d.file.uri,
-					Range: protocol.Range{
-						Start: start,
-						End:   end,
-					},
-				},
+// prefixRef returns a substitutionRef for expressions such as
+// $(<prefix>.<name>.path), with at least minSegments name segments, referring
+// to an identifier of the given kind declared in the same document.
+func prefixRef(kind identifierKind, prefix string, minSegments int) *substitutionRef {
+	return &substitutionRef{
+		handler: func(d *Document, s substitution) []reference {
+			names := s.names()
+			if len(names) < max(minSegments, 2) || names[0].value != prefix {
+				return nil
+			}
+			name := names[1]
+			return []reference{
 				{
-					URI: d.file.uri,
-					Range: protocol.Range{
-						Start: d.OffsetPosition(match[2]),
-						End:   d.OffsetPosition(match[3]),
-					},
+					kind:     kind,
+					name:     name.value,
+					accessor: s.accessor(1),
+					ident:    d.getIdent(&kindNameLocator{kind, name.value}),
+					offsets:  []int{s.start, s.end, name.start, name.end},
 				},
-			})
-		} else {
-			d.file.danglingRefs[name] = struct{}{}
-		}
-		d.references = append(d.references, reference{
-			kind:    r.kind,
-			name:    name,
-			ident:   id,
-			start:   start,
-			end:     end,
-			offsets: match,
-		})
+			}
+		},
 	}
 }
 
@@ -188,24 +209,211 @@ func (r *pathRef2) find(d *Document) {
 	})
 }
 
+// taskRefRef returns a pathRef which resolves the Task names found in the
+// given path, as in `taskRef.name`, to the Tasks declared in the workspace.
+func taskRefRef(path string) *pathRef {
+	return resourceRefRef(path, IdentKindTask)
+}
+
+// resourceRefRef returns a pathRef which resolves the names found in the
+// given path to the resources of the given kind declared in the workspace.
+func resourceRefRef(path string, kind identifierKind) *pathRef {
+	return &pathRef{
+		path:  mustPathString(path),
+		depth: strings.Count(path, "[*]"),
+		handler: func(d *Document, v interface{}, node ast.Node) []reference {
+			s, ok := v.(string)
+			if !ok {
+				return nil
+			}
+			prange, offsets := d.getNodeRange(node)
+			return []reference{
+				{
+					kind:    kind,
+					name:    s,
+					ident:   d.file.workspace.getIdent(&kindNameLocator{kind, s}),
+					start:   prange.Start,
+					end:     prange.End,
+					offsets: offsets,
+				},
+			}
+		},
+	}
+}
+
+// pipelineTaskParamRef returns a pathRef2 which resolves the parameter names
+// located by the given paths, relative to each pipeline task, to the
+// parameters declared by the Task referenced by the pipeline task.
+func pipelineTaskParamRef(paths ...string) *pathRef2 {
+	ps := []*yaml.Path{mustPathString("$.spec.tasks[*]")}
+	for _, p := range paths {
+		ps = append(ps, mustPathString(p))
+	}
+	return &pathRef2{
+		paths: ps,
+		handler: func(d *Document, nodes []yaml_helper.ParsedNode) []reference {
+			nameNode := nodes[len(nodes)-1]
+			s, ok := nameNode.Value.(string)
+			if !ok {
+				return nil
+			}
+
+			parent := nodes[1].Value.(map[string]interface{})
+			pt := PipelineTask(parent, nodes[1].Node)
+			taskName := pt.TaskRef()
+			if pt.task(d.file.workspace) == nil {
+				// parameters can't be checked against an unknown Task,
+				// which is already reported by the taskRef reference.
+				return nil
+			}
+
+			prange, offsets := d.getNodeRange(nameNode.Node)
+			return []reference{
+				{
+					kind: IdentKindParam,
+					name: s,
+					ident: d.file.workspace.getIdent(&taskParamLocator{
+						name:     s,
+						taskName: taskName,
+					}),
+					start:   prange.Start,
+					end:     prange.End,
+					offsets: offsets,
+				},
+			}
+		},
+	}
+}
+
 // references is the list of referenceResolver used to find all references
 // in a given Tekton Document.
 var references = []referenceResolver{
-	&regexpRef{
-		kind:  IdentKindParam,
-		regex: regexp.MustCompile(`\$\(params\.(.*?)(\[\*\])?\)`),
+	prefixRef(IdentKindParam, "params", 2),
+	&substitutionRef{
+		// object parameter keys, as in $(params.name.key). Unknown keys
+		// are not considered references, since they are reported by
+		// paramTypeDiagnostics.
+		handler: func(d *Document, s substitution) []reference {
+			names := s.names()
+			if len(names) < 3 || names[0].value != "params" {
+				return nil
+			}
+			param, key := names[1], names[2]
+			id := d.getIdent(&paramPropertyLocator{param.value, key.value})
+			if id == nil {
+				return nil
+			}
+			return []reference{
+				{
+					kind:    IdentKindParamProperty,
+					name:    key.value,
+					ident:   id,
+					offsets: []int{key.start, key.end, key.start, key.end},
+				},
+			}
+		},
 	},
-	&regexpRef{
-		kind:  IdentKindResult,
-		regex: regexp.MustCompile(`\$\(results\.(.*?)\.(.*?)\)`),
+	&substitutionRef{
+		// context variables, as in $(context.taskRun.name)
+		handler: func(d *Document, s substitution) []reference {
+			names := s.names()
+			if len(names) < 3 || names[0].value != "context" {
+				return nil
+			}
+			first, last := names[1], names[len(names)-1]
+			name := string(d.Bytes()[first.start:last.end])
+			id := d.getIdent(&kindNameLocator{IdentKindContext, name})
+			if id != nil && !id.meta.(*contextVariable).availableAt(d, s.start) {
+				// as in Task variables outside of embedded task specs
+				id = nil
+			}
+			return []reference{
+				{
+					kind:    IdentKindContext,
+					name:    name,
+					ident:   id,
+					offsets: []int{s.start, s.end, first.start, last.end},
+				},
+			}
+		},
 	},
-	&regexpRef{
-		kind:  IdentKindWorkspace,
-		regex: regexp.MustCompile(`\$\(workspaces\.(.*?)\.(.*?)\)`),
+	prefixRef(IdentKindStep, "steps", 3),
+	&substitutionRef{
+		// step results, as in $(steps.name.results.result.path), or
+		// $(step.results.result.path) within the step itself
+		handler: func(d *Document, s substitution) []reference {
+			names := s.names()
+			var step string
+			var result segment
+			switch {
+			case len(names) >= 4 && names[0].value == "steps" && names[2].value == "results":
+				step, result = names[1].value, names[3]
+			case len(names) >= 3 && names[0].value == "step" && names[1].value == "results":
+				st := d.enclosingStep(s.start)
+				if st == nil {
+					return nil
+				}
+				step, result = st.Name(), names[2]
+			default:
+				return nil
+			}
+			return []reference{
+				{
+					kind: IdentKindStepResult,
+					name: result.value,
+					ident: d.getIdent(&stepResultLocator{
+						name: result.value,
+						step: step,
+					}),
+					offsets: []int{result.start, result.end, result.start, result.end},
+				},
+			}
+		},
 	},
-	&regexpRef{
-		kind:  IdentKindPipelineTask,
-		regex: regexp.MustCompile(`\$\(tasks\.(.*?)\.(.*?)\.(.*?)\)`),
+	&substitutionRef{
+		// Task results, as in $(results.name.path). Pipeline results can't
+		// be referred to, and results of embedded task specs are not
+		// identifiers.
+		handler: func(d *Document, s substitution) []reference {
+			if d.kind != "task" {
+				return nil
+			}
+			return prefixRef(IdentKindResult, "results", 3).handler(d, s)
+		},
+	},
+	prefixRef(IdentKindWorkspace, "workspaces", 3),
+	prefixRef(IdentKindPipelineTask, "tasks", 3),
+	&substitutionRef{
+		// results of the Task referenced by a pipeline task, as in
+		// $(tasks.name.results.result)
+		handler: func(d *Document, s substitution) []reference {
+			names := s.names()
+			if len(names) < 4 || names[0].value != "tasks" || names[2].value != "results" {
+				return nil
+			}
+			id := d.getIdent(&kindNameLocator{IdentKindPipelineTask, names[1].value})
+			if id == nil {
+				return nil
+			}
+			pt, ok := id.meta.(*pipelineTask)
+			if !ok || pt.task(d.file.workspace) == nil {
+				// results of embedded task specs are not identifiers,
+				// and unknown Tasks are reported by the taskRef reference.
+				return nil
+			}
+			result := names[3]
+			return []reference{
+				{
+					kind: IdentKindResult,
+					name: result.value,
+					ident: d.file.workspace.getIdent(&taskResultLocator{
+						name:     result.value,
+						taskName: pt.TaskRef(),
+					}),
+					offsets: []int{result.start, result.end, result.start, result.end},
+				},
+			}
+		},
 	},
 	&pathRef{
 		path:  mustPathString("$.spec.tasks[*].workspaces[*]"),
@@ -262,33 +470,23 @@ var references = []referenceResolver{
 			}
 		},
 	},
-	&pathRef{
-		path:  mustPathString("$.spec.tasks[*].taskRef.name"),
-		depth: 1,
-		handler: func(d *Document, v interface{}, node ast.Node) []reference {
-			s, ok := v.(string)
-			if !ok {
-				return nil
-			}
-			prange, offsets := d.getNodeRange(node)
-			return []reference{
-				{
-					kind:    IdentKindTask,
-					name:    s,
-					ident:   d.file.workspace.getIdent(&kindNameLocator{IdentKindTask, s}),
-					start:   prange.Start,
-					end:     prange.End,
-					offsets: offsets,
-				},
-			}
-		},
-	},
+	taskRefRef("$.spec.tasks[*].taskRef.name"),
+	taskRefRef("$.spec.finally[*].taskRef.name"),
+	// PipelineRuns embedding their Pipeline
+	taskRefRef("$.spec.pipelineSpec.tasks[*].taskRef.name"),
+	taskRefRef("$.spec.pipelineSpec.finally[*].taskRef.name"),
+	// TaskRuns
+	taskRefRef("$.spec.taskRef.name"),
+	// PipelineRuns
+	resourceRefRef("$.spec.pipelineRef.name", IdentKindPipeline),
+	pipelineTaskParamRef("$.params[*]", "$.name"),
+	pipelineTaskParamRef("$.matrix.params[*]", "$.name"),
+	pipelineTaskParamRef("$.matrix.include[*]", "$.params[*]", "$.name"),
 	&pathRef2{
 		paths: []*yaml.Path{
 			mustPathString("$.spec.tasks[*]"),
-			mustPathString("$.params[*]"),
+			mustPathString("$.workspaces[*]"),
 			mustPathString("$.name"),
-			// mustPathString("$.spec.tasks[*].params[*].name"),
 		},
 		handler: func(d *Document, nodes []yaml_helper.ParsedNode) []reference {
 			s, ok := nodes[3].Value.(string)
@@ -297,28 +495,19 @@ var references = []referenceResolver{
 			}
 
 			parent := nodes[1].Value.(map[string]interface{})
-			tr := parent["taskRef"]
-			var trm map[string]interface{}
-			if tr != nil {
-				trm, _ = tr.(map[string]interface{})
-			}
-			var taskName string
-			if trm != nil {
-				ni := trm["name"]
-				if ni != nil {
-					taskName, _ = ni.(string)
-				}
+			pt := PipelineTask(parent, nodes[1].Node)
+			if pt.task(d.file.workspace) == nil {
+				return nil
 			}
 
 			prange, offsets := d.getNodeRange(nodes[3].Node)
 			return []reference{
 				{
-					kind: IdentKindParam,
+					kind: IdentKindWorkspace,
 					name: s,
-					// TODO: get param from the correct task :)
-					ident: d.file.workspace.getIdent(&taskParamLocator{
+					ident: d.file.workspace.getIdent(&taskWorkspaceLocator{
 						name:     s,
-						taskName: taskName,
+						taskName: pt.TaskRef(),
 					}),
 					start:   prange.Start,
 					end:     prange.End,
@@ -345,6 +534,7 @@ func wholeReferences(id *identifier) []protocol.Location {
 
 func (d *Document) solveReferences() {
 	d.references = d.references[:0]
+	d.substitutions = parseSubstitutions(d.Bytes()[d.offset:d.offset+d.size], d.offset)
 	for _, ref := range references {
 		ref.find(d)
 	}
@@ -359,8 +549,10 @@ func (d *Document) findIdentifier(pos protocol.Position) *identifier {
 	return nil
 }
 
+// findReferences returns the references of the identifier defined or
+// referred to in the given position.
 func (d *Document) findReferences(pos protocol.Position) []protocol.Location {
-	return wholeReferences(d.findIdentifier(pos))
+	return wholeReferences(d.identifierAt(pos))
 }
 
 // cmpPos provides a strict partial order for TextDocument positions.
@@ -1,18 +1,177 @@
 package tekton
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	protocol "github.com/tliron/glsp/protocol_3_16"
 )
 
+// ErrInvalidRename is wrapped by the errors of renames which can't be
+// applied, because the new name is invalid or already taken.
+var ErrInvalidRename = errors.New("invalid rename")
+
+var (
+	// dns1123LabelRegexp matches names of containers and pipeline tasks.
+	dns1123LabelRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+	// dns1123SubdomainRegexp matches names of Kubernetes objects, such as
+	// Tasks and Pipelines.
+	dns1123SubdomainRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+	// paramNameRegexp matches parameter names, which may contain dots if
+	// referred to with the bracket notation.
+	paramNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
+
+	// resultNameRegexp matches result names.
+	resultNameRegexp = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+
+	// variableNameRegexp matches names which are referred to in variable
+	// substitutions, such as workspaces.
+	variableNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+)
+
+// validateName returns an error if name isn't a valid name for identifiers
+// of the kind.
+func (k identifierKind) validateName(name string) error {
+	switch k {
+	case IdentKindTask, IdentKindPipeline:
+		if len(name) > 253 || !dns1123SubdomainRegexp.MatchString(name) {
+			return fmt.Errorf(
+				"%w: %s name %q must consist of at most 253 lowercase alphanumeric characters, '-' or '.', starting and ending with an alphanumeric character",
+				ErrInvalidRename, k, name,
+			)
+		}
+	case IdentKindPipelineTask, IdentKindStep, IdentKindSidecar:
+		if len(name) > 63 || !dns1123LabelRegexp.MatchString(name) {
+			return fmt.Errorf(
+				"%w: %s name %q must consist of at most 63 lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character",
+				ErrInvalidRename, k, name,
+			)
+		}
+	case IdentKindParam, IdentKindParamProperty:
+		if !paramNameRegexp.MatchString(name) {
+			return fmt.Errorf(
+				"%w: %s name %q must consist of alphanumeric characters, '-', '_' or '.', starting with a letter or '_'",
+				ErrInvalidRename, k, name,
+			)
+		}
+	case IdentKindResult, IdentKindStepResult:
+		if !resultNameRegexp.MatchString(name) {
+			return fmt.Errorf(
+				"%w: %s name %q must consist of alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character",
+				ErrInvalidRename, k, name,
+			)
+		}
+	case IdentKindWorkspace:
+		if !variableNameRegexp.MatchString(name) {
+			return fmt.Errorf(
+				"%w: %s name %q must consist of alphanumeric characters, '-' or '_', starting with a letter or '_'",
+				ErrInvalidRename, k, name,
+			)
+		}
+	}
+	return nil
+}
+
+// conflictLocator returns a locator of the identifiers which would conflict
+// with the given identifier if it were renamed to name, that is, the ones
+// of the same kind and name in its scope.
+func conflictLocator(id *identifier, name string) identLocator {
+	switch m := id.meta.(type) {
+	case *stepResult:
+		return &stepResultLocator{name: name, step: m.step}
+	case *paramProperty:
+		return &paramPropertyLocator{param: m.param.Name(), key: name}
+	}
+	return &kindNameLocator{id.kind, name}
+}
+
+// validateRename returns an error if the identifier can't be renamed to
+// name, because the name is invalid, taken by another identifier in its
+// scope, or can't be written in the references to the identifier.
+func (d *Document) validateRename(id *identifier, name string) error {
+	if id.builtin {
+		return fmt.Errorf("%w: %s %s is built-in", ErrInvalidRename, id.kind, id.meta.Name())
+	}
+	if name == id.meta.Name() {
+		return nil
+	}
+	if err := id.kind.validateName(name); err != nil {
+		return err
+	}
+
+	w := d.file.workspace
+	f := w.File(id.location.URI)
+	if f == nil {
+		return nil
+	}
+
+	var taken *identifier
+	switch id.kind {
+	case IdentKindTask, IdentKindPipeline:
+		// Tasks and Pipelines are referred to by name from anywhere in the
+		// workspace, which may already declare the name more than once
+		if ids := w.getIdents(conflictLocator(id, name)); len(ids) != 0 {
+			taken = ids[0]
+		}
+	case IdentKindStep, IdentKindSidecar:
+		// steps and sidecars are containers of the same pod
+		if decl := f.findDoc(id.location.Range.Start); decl != nil {
+			taken = decl.getIdent(&kindNameLocator{IdentKindStep, name})
+			if taken == nil {
+				taken = decl.getIdent(&kindNameLocator{IdentKindSidecar, name})
+			}
+		}
+	default:
+		if decl := f.findDoc(id.location.Range.Start); decl != nil {
+			taken = decl.getIdent(conflictLocator(id, name))
+		}
+	}
+	if taken != nil {
+		return fmt.Errorf("%w: %s %s already exists", ErrInvalidRename, taken.kind, name)
+	}
+
+	if id.kind == IdentKindParam && strings.Contains(name, ".") {
+		// $(params.name) would become $(params.na.me)
+		for _, ref := range id.references {
+			rf := w.File(ref[1].URI)
+			if rf == nil {
+				continue
+			}
+			start := rf.PositionOffset(ref[1].Range.Start)
+			if start > 0 && rf.Bytes()[start-1] == '.' {
+				return fmt.Errorf(
+					"%w: parameter names containing '.' must be referred to as $(params[%q]), found at line %d",
+					ErrInvalidRename, name, ref[1].Range.Start.Line+1,
+				)
+			}
+		}
+	}
+	return nil
+}
+
 // check File.PrepareRename
 func (d *Document) prepareRename(pos protocol.Position) *protocol.Location {
-	id := d.findIdentifier(pos)
-	if id == nil {
+	if id := d.findIdentifier(pos); id != nil {
+		return &id.location
+	}
+
+	// only the name portion of a reference is edited, as `foo` in
+	// $(params.foo)
+	ref := d.referenceInPosition(pos)
+	if ref == nil || ref.ident == nil || ref.ident.builtin {
 		return nil
 	}
-	return &id.location
+	return &protocol.Location{
+		URI: d.file.uri,
+		Range: protocol.Range{
+			Start: d.OffsetPosition(ref.offsets[2]),
+			End:   d.OffsetPosition(ref.offsets[3]),
+		},
+	}
 }
 
 // check File.Rename
@@ -20,14 +179,17 @@ func (d *Document) rename(
 	pos protocol.Position,
 	newName string,
 ) (*protocol.WorkspaceEdit, error) {
-	// maybe support renaming from references as well?
-	id := d.findIdentifier(pos)
+	id := d.identifierAt(pos)
 	if id == nil {
 		return nil, fmt.Errorf("nothing to rename")
 	}
+	if err := d.validateRename(id, newName); err != nil {
+		return nil, err
+	}
 
 	changes := map[string][]protocol.TextEdit{}
-	changes[d.file.uri] = append(changes[d.file.uri],
+	uri := id.location.URI
+	changes[uri] = append(changes[uri],
 		protocol.TextEdit{
 			Range:   id.location.Range,
 			NewText: newName,
@@ -0,0 +1,223 @@
+package tekton
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestRenameFromReference(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: foo
+  steps:
+  - name: echo
+    image: busybox
+    script: echo $(params.foo) $(context.task.name)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+    params:
+    - name: foo
+      value: bar
+`)
+	w.Lint()
+	task := w.File("file://task.yaml")
+
+	// $(params.foo)
+	ref := protocol.Position{Line: 10, Character: 20}
+
+	loc := task.PrepareRename(ref)
+	want := protocol.Range{
+		Start: protocol.Position{Line: 10, Character: 26},
+		End:   protocol.Position{Line: 10, Character: 29},
+	}
+	if loc == nil || loc.Range != want {
+		t.Errorf("PrepareRename: got %v, want %v", loc, want)
+	}
+
+	if got := len(task.FindReferences(ref)); got != 2 {
+		t.Errorf("FindReferences: got %d references, want 2", got)
+	}
+
+	edit, err := task.Rename(ref, "baz")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	var uris []string
+	for uri, edits := range edit.Changes {
+		for range edits {
+			uris = append(uris, uri)
+		}
+	}
+	slices.Sort(uris)
+	wantURIs := []string{"file://pipe.yaml", "file://task.yaml", "file://task.yaml"}
+	if !reflect.DeepEqual(uris, wantURIs) {
+		t.Errorf("Rename: got edits in %v, want %v", uris, wantURIs)
+	}
+
+	// the Task, from its taskRef in another file
+	edit, err = w.Rename("file://pipe.yaml", protocol.Position{Line: 8, Character: 14}, "compile")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	wantEdit := protocol.TextEdit{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 3, Character: 8},
+			End:   protocol.Position{Line: 3, Character: 13},
+		},
+		NewText: "compile",
+	}
+	if got := edit.Changes["file://task.yaml"]; len(got) != 1 || got[0] != wantEdit {
+		t.Errorf("Rename: got %v in task.yaml, want %v", got, wantEdit)
+	}
+
+	// built-in context variables
+	builtin := protocol.Position{Line: 10, Character: 40}
+	if loc := task.PrepareRename(builtin); loc != nil {
+		t.Errorf("PrepareRename: got %v, want nil for built-ins", loc)
+	}
+	if _, err := task.Rename(builtin, "x"); err == nil {
+		t.Errorf("Rename: want error for built-ins")
+	}
+}
+
+func TestRenameValidation(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: foo
+  - name: bar
+  steps:
+  - name: echo
+    image: busybox
+    script: echo $(params.foo) $(params.bar)
+  sidecars:
+  - name: proxy
+    image: envoy
+---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: test
+spec:
+  steps:
+  - name: test
+    image: busybox
+`)
+	w.UpsertFile("file://dup.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: dup
+---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: dup
+`)
+	w.Lint()
+	task := w.File("file://task.yaml")
+
+	tests := []struct {
+		name string
+		pos  protocol.Position
+		new  string
+		ok   bool
+	}{
+		{"valid param", protocol.Position{Line: 6, Character: 10}, "baz", true},
+		{"same name", protocol.Position{Line: 6, Character: 10}, "foo", true},
+		{"invalid param", protocol.Position{Line: 6, Character: 10}, "1foo", false},
+		{"param conflict", protocol.Position{Line: 6, Character: 10}, "bar", false},
+		{"dotted param", protocol.Position{Line: 6, Character: 10}, "foo.bar", false},
+		{"invalid task", protocol.Position{Line: 3, Character: 8}, "Build", false},
+		{"task conflict", protocol.Position{Line: 3, Character: 8}, "test", false},
+		{"duplicated task conflict", protocol.Position{Line: 3, Character: 8}, "dup", false},
+		{"dotted task", protocol.Position{Line: 3, Character: 8}, "build.v2", true},
+		{"long task", protocol.Position{Line: 3, Character: 8}, strings.Repeat("a", 64), true},
+		{"too long task", protocol.Position{Line: 3, Character: 8}, strings.Repeat("a", 254), false},
+		{"dotted step", protocol.Position{Line: 9, Character: 10}, "echo.v2", false},
+		{"step as sidecar", protocol.Position{Line: 9, Character: 10}, "proxy", false},
+		{"step in other task", protocol.Position{Line: 9, Character: 10}, "test", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := task.Rename(tc.pos, tc.new)
+			if tc.ok && err != nil {
+				t.Errorf("Rename(%q): %v", tc.new, err)
+			}
+			if !tc.ok && !errors.Is(err, ErrInvalidRename) {
+				t.Errorf("Rename(%q): got %v, want ErrInvalidRename", tc.new, err)
+			}
+		})
+	}
+}
+
+func TestRenameTaskRefs(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  steps:
+  - name: echo
+    image: busybox
+`)
+	w.UpsertFile("file://run.yaml", `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: run
+spec:
+  pipelineSpec:
+    tasks:
+    - name: first
+      taskRef:
+        name: build
+    finally:
+    - name: last
+      taskRef:
+        name: build
+---
+apiVersion: tekton.dev/v1
+kind: TaskRun
+metadata:
+  name: taskrun
+spec:
+  taskRef:
+    name: build
+`)
+	w.Lint()
+
+	edit, err := w.Rename("file://task.yaml", protocol.Position{Line: 3, Character: 8}, "compile")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	var lines []protocol.UInteger
+	for _, e := range edit.Changes["file://run.yaml"] {
+		lines = append(lines, e.Range.Start.Line)
+	}
+	slices.Sort(lines)
+	if want := []protocol.UInteger{9, 13, 21}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("Rename: got edits in lines %v of run.yaml, want %v", lines, want)
+	}
+}
@@ -2,13 +2,17 @@ package tekton
 
 import (
 	"fmt"
+
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml"
+	protocol "github.com/tliron/glsp/protocol_3_16"
 )
 
 type IdentResult StringMap
 
 var _ Meta = IdentResult{}
 
-func (p IdentResult) Completions() []completion {
+func (p IdentResult) Completions(_ *Document) []completion {
 	return []completion{
 		{
 			text: fmt.Sprintf("$(results.%s.path)", p.Name()),
@@ -21,6 +25,19 @@ func (p IdentResult) Name() string {
 	return n
 }
 
+func (p IdentResult) Type() string {
+	if t, ok := StringMap(p)["type"].(string); ok {
+		return t
+	}
+	return "string"
+}
+
+// HasValue returns true if the result declares its value.
+func (p IdentResult) HasValue() bool {
+	_, ok := StringMap(p)["value"]
+	return ok
+}
+
 func (p IdentResult) Description() string {
 	d, _ := StringMap(p)["description"].(string)
 	return d
@@ -33,3 +50,120 @@ func (p IdentResult) Documentation() string {
 		p.Description(),
 	)
 }
+
+// IdentPipelineResult is a result declared by a Pipeline, whose value is set
+// from the results of its pipeline tasks. Unlike Task results, it can't be
+// referred to from within the Pipeline itself.
+type IdentPipelineResult StringMap
+
+var _ Meta = IdentPipelineResult{}
+
+func (p IdentPipelineResult) Completions(_ *Document) []completion {
+	return []completion{}
+}
+
+func (p IdentPipelineResult) Name() string {
+	n, _ := StringMap(p)["name"].(string)
+	return n
+}
+
+func (p IdentPipelineResult) Type() string {
+	if t, ok := StringMap(p)["type"].(string); ok {
+		return t
+	}
+	return "string"
+}
+
+// Value returns the value of the result, or nil if it's not set.
+func (p IdentPipelineResult) Value() interface{} {
+	return StringMap(p)["value"]
+}
+
+func (p IdentPipelineResult) Description() string {
+	d, _ := StringMap(p)["description"].(string)
+	return d
+}
+
+func (p IdentPipelineResult) Documentation() string {
+	value, _ := yaml.Marshal(map[string]interface{}{"value": p.Value()})
+	return fmt.Sprintf(
+		"```yaml\nname: %s\ntype: %s\n%s%s\n```",
+		p.Name(),
+		p.Type(),
+		value,
+		p.Description(),
+	)
+}
+
+// invalidResultReferences returns the variable substitutions in any string
+// within v which are not references to pipeline task results, such as
+// $(tasks.name.results.result) or $(finally.name.results.result).
+func invalidResultReferences(v interface{}) []string {
+	var invalid []string
+	switch t := v.(type) {
+	case string:
+		for _, s := range parseSubstitutions([]byte(t), 0) {
+			names := s.names()
+			if len(names) >= 4 &&
+				(names[0].value == "tasks" || names[0].value == "finally") &&
+				names[2].value == "results" {
+				continue
+			}
+			invalid = append(invalid, t[s.start:s.end])
+		}
+	case []interface{}:
+		for _, e := range t {
+			invalid = append(invalid, invalidResultReferences(e)...)
+		}
+	case map[string]interface{}:
+		for _, e := range t {
+			invalid = append(invalid, invalidResultReferences(e)...)
+		}
+	}
+	return invalid
+}
+
+// pipelineResultDiagnostics reports Pipeline results without a value, or
+// whose value refers to anything other than pipeline task results.
+func (d *Document) pipelineResultDiagnostics(c chan<- *protocol.Diagnostic) {
+	if d.kind != "pipeline" {
+		return
+	}
+	paths := []*yaml.Path{mustPathString("$.spec.results[*]")}
+	yaml_helper.VisitPath(d.ast.Body, paths, func(nodes []yaml_helper.ParsedNode) {
+		rm, ok := nodes[1].Value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		r := IdentPipelineResult(rm)
+
+		node, err := mustPathString("$.value").FilterNode(nodes[1].Node)
+		if err != nil || node == nil {
+			node, err = mustPathString("$.name").FilterNode(nodes[1].Node)
+			if err != nil || node == nil {
+				return
+			}
+			rng, _ := d.getNodeRange(node)
+			c <- newDiagnostic(
+				rng,
+				protocol.DiagnosticSeverityError,
+				"pipeline-result",
+				"pipeline result %s must have a value",
+				r.Name(),
+			)
+			return
+		}
+
+		rng, _ := d.getNodeRange(node)
+		for _, ref := range invalidResultReferences(r.Value()) {
+			c <- newDiagnostic(
+				rng,
+				protocol.DiagnosticSeverityError,
+				"pipeline-result",
+				"pipeline result %s can only refer to task results, found %s",
+				r.Name(),
+				ref,
+			)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package tekton
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestPipelineResults(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: digest
+  steps:
+  - name: build
+    image: busybox
+    script: echo -n sha > $(results.digest.path)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  params:
+  - name: tag
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+  results:
+  - name: digest
+    value: $(tasks.build.results.digest)
+  - name: tag
+    value: $(params.tag)
+  - name: missing
+  - name: unknown
+    value: $(tasks.build.results.unknown)
+`)
+	w.Lint()
+
+	got := diagnosticMessages(w.File("file://pipe.yaml"))
+	slices.Sort(got)
+	want := []string{
+		"pipeline result missing must have a value",
+		"pipeline result tag can only refer to task results, found $(params.tag)",
+		"unknown result unknown",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	id := w.File("file://pipe.yaml").getIdent(&kindNameLocator{IdentKindResult, "digest"})
+	if id == nil {
+		t.Fatalf("pipeline result digest not found")
+	}
+	if _, ok := id.meta.(IdentPipelineResult); !ok {
+		t.Errorf("pipeline result meta: got %T, want IdentPipelineResult", id.meta)
+	}
+	doc := id.meta.Documentation()
+	if strings.Contains(doc, ".path") || !strings.Contains(doc, "value: $(tasks.build.results.digest)") {
+		t.Errorf("Documentation: got %q, want pipeline result value", doc)
+	}
+	cs := completionTexts(w.File("file://pipe.yaml"), protocol.Position{Line: 12, Character: 11})
+	if slices.Contains(cs, "$(results.digest.path)") {
+		t.Errorf("Completions: got %v, pipeline results must not be suggested", cs)
+	}
+	if w.getIdent(&taskResultLocator{name: "digest", taskName: "pipeline"}) != nil {
+		t.Errorf("pipeline result digest found as a task result")
+	}
+}
+
+func TestPipelineRunPipelineRef(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: build
+    taskRef:
+      name: build
+  results:
+  - name: digest
+    value: $(tasks.build.results.digest)
+`)
+	w.UpsertFile("file://run.yaml", `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: run
+spec:
+  pipelineRef:
+    name: pipeline
+`)
+	w.Lint()
+
+	if got := diagnosticMessages(w.File("file://pipe.yaml")); slices.Contains(got, "unused pipeline pipeline") {
+		t.Errorf("Diagnostics: got %v, pipelines must not be reported as unused", got)
+	}
+
+	f := w.File("file://run.yaml")
+	pos := protocol.Position{Line: 6, Character: 12}
+	def := f.Definition(pos)
+	if def == nil || def.URI != "file://pipe.yaml" || def.Range.Start.Line != 3 {
+		t.Errorf("Definition(%v): got %v, want pipe.yaml line 3", pos, def)
+	}
+	hover := f.Hover(pos)
+	if hover == nil || !strings.Contains(*hover, "- name: digest\n  type: string\n  value: $(tasks.build.results.digest)") {
+		t.Errorf("Hover(%v): got %v, want the pipeline results", pos, hover)
+	}
+}
@@ -0,0 +1,103 @@
+package tekton
+
+import (
+	"strings"
+
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml/ast"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// nodeRange returns the range of the given node, from its first up to its
+// last non-blank character.
+func (d *Document) nodeRange(node ast.Node) protocol.Range {
+	first, next := yaml_helper.Bounds(node)
+	// the contents of block scalars are positioned at column 0
+	start := d.PositionOffset(protocol.Position{
+		Line:      uint32(first.Position.Line - 1),
+		Character: uint32(max(first.Position.Column-1, 0)),
+	})
+	end := d.offset + d.size
+	if next != nil {
+		end = d.PositionOffset(protocol.Position{
+			Line:      uint32(next.Position.Line - 1),
+			Character: uint32(next.Position.Column - 1),
+		})
+	}
+	text := d.Bytes()
+	// tokens after the last one of the document, such as comments, may be
+	// positioned beyond it
+	end = min(end, d.offset+d.size, len(text))
+	for start < end && strings.ContainsRune(" \t\r\n", rune(text[start])) {
+		start++
+	}
+	for end > start && strings.ContainsRune(" \t\r\n", rune(text[end-1])) {
+		end--
+	}
+	return protocol.Range{
+		Start: d.OffsetPosition(start),
+		End:   d.OffsetPosition(end),
+	}
+}
+
+// documentRange returns the range of the whole document, excluding the
+// separator of the next one.
+func (d *Document) documentRange() protocol.Range {
+	lines := strings.Split(string(d.TextDocument), "\n")
+	start := d.OffsetPosition(d.offset)
+	end := lastContentLine(lines, start.Line, d.OffsetPosition(max(d.offset, d.offset+d.size-1)).Line)
+	return protocol.Range{
+		Start: start,
+		End:   protocol.Position{Line: end, Character: uint32(len(lines[end]))},
+	}
+}
+
+// selectionRange returns the ranges enclosing the given position, from the
+// innermost to the whole document: the name referred to by a variable
+// substitution, the substitution, and each YAML node up to the root, such
+// as the scalar value, its mapping entry, the step and the steps list.
+func (d *Document) selectionRange(pos protocol.Position) *protocol.SelectionRange {
+	ranges := []protocol.Range{d.documentRange()}
+	for _, n := range yaml_helper.FindPath(d.ast.Body, int(pos.Line)+1, int(pos.Character)+1) {
+		ranges = append(ranges, d.nodeRange(n))
+	}
+
+	offset := d.PositionOffset(pos)
+	for _, s := range d.substitutions {
+		if offset < s.start || offset > s.end {
+			continue
+		}
+		ranges = append(ranges, protocol.Range{
+			Start: d.OffsetPosition(s.start),
+			End:   d.OffsetPosition(s.end),
+		})
+		for _, seg := range s.segments {
+			if offset < seg.start || offset > seg.end {
+				continue
+			}
+			start, end := seg.start, seg.end
+			if ref := d.substitutionReference(seg); ref != nil {
+				start, end = ref.offsets[2], ref.offsets[3]
+			}
+			ranges = append(ranges, protocol.Range{
+				Start: d.OffsetPosition(start),
+				End:   d.OffsetPosition(end),
+			})
+			break
+		}
+		break
+	}
+
+	var res *protocol.SelectionRange
+	for _, r := range ranges {
+		if res != nil && (r == res.Range || cmpPos(r.Start, res.Range.Start) || cmpPos(res.Range.End, r.End)) {
+			// not narrower than its parent
+			continue
+		}
+		res = &protocol.SelectionRange{
+			Range:  r,
+			Parent: res,
+		}
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestSelectionRanges(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  params:
+  - name: foo
+  steps:
+  - name: echo
+    image: busybox
+    script: echo $(params.foo)
+  - name: other
+    image: busybox
+---
+kind: Pipeline
+`))
+
+	rng := func(sl, sc, el, ec uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: sl, Character: sc},
+			End:   protocol.Position{Line: el, Character: ec},
+		}
+	}
+
+	got := f.SelectionRanges([]protocol.Position{
+		// `foo` in $(params.foo)
+		{Line: 10, Character: 28},
+	})
+	if len(got) != 1 {
+		t.Fatalf("SelectionRanges: got %d ranges, want 1", len(got))
+	}
+	var ranges []protocol.Range
+	for r := &got[0]; r != nil; r = r.Parent {
+		ranges = append(ranges, r.Range)
+	}
+	want := []protocol.Range{
+		rng(10, 26, 10, 29), // foo
+		rng(10, 17, 10, 30), // $(params.foo)
+		rng(10, 12, 10, 30), // the script value
+		rng(10, 4, 10, 30),  // script: ...
+		rng(8, 4, 10, 30),   // the step
+		rng(8, 2, 12, 18),   // the steps list
+		rng(7, 2, 12, 18),   // steps: ...
+		rng(5, 2, 12, 18),   // spec mapping
+		rng(4, 0, 12, 18),   // spec: ...
+		rng(0, 0, 12, 18),   // the document
+	}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("SelectionRanges:\ngot  %v\nwant %v", ranges, want)
+	}
+}
+
+func TestSelectionRangesBlockScalar(t *testing.T) {
+	f := parseFile(file.TextDocument(`kind: Task
+spec:
+  steps:
+  - name: echo
+    script: |
+      echo hello
+
+      echo world
+  # trailing comment
+`))
+
+	got := f.SelectionRanges([]protocol.Position{{Line: 5, Character: 7}})
+	var ranges []protocol.Range
+	for r := &got[0]; r != nil; r = r.Parent {
+		ranges = append(ranges, r.Range)
+	}
+	want := []protocol.Range{
+		// the script contents, up to its last line
+		{
+			Start: protocol.Position{Line: 5, Character: 6},
+			End:   protocol.Position{Line: 7, Character: 16},
+		},
+		// the script value, from its indicator
+		{
+			Start: protocol.Position{Line: 4, Character: 12},
+			End:   protocol.Position{Line: 7, Character: 16},
+		},
+	}
+	if len(ranges) < 2 || !reflect.DeepEqual(ranges[:2], want) {
+		t.Errorf("SelectionRanges:\ngot  %v\nwant %v first", ranges, want)
+	}
+}
@@ -0,0 +1,164 @@
+package tekton
+
+import (
+	"slices"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// semanticTokenType is the index of a token type in SemanticTokenTypes.
+type semanticTokenType int
+
+const (
+	tokenNamespace semanticTokenType = iota
+	tokenParameter
+	tokenProperty
+	tokenVariable
+	tokenFunction
+	tokenMacro
+	tokenKeyword
+)
+
+// SemanticTokenTypes is the legend of token types used by SemanticTokens.
+var SemanticTokenTypes = []string{
+	string(protocol.SemanticTokenTypeNamespace),
+	string(protocol.SemanticTokenTypeParameter),
+	string(protocol.SemanticTokenTypeProperty),
+	string(protocol.SemanticTokenTypeVariable),
+	string(protocol.SemanticTokenTypeFunction),
+	string(protocol.SemanticTokenTypeMacro),
+	string(protocol.SemanticTokenTypeKeyword),
+}
+
+const (
+	// tokenModifierDefaultLibrary marks identifiers provided by Tekton,
+	// such as context variables.
+	tokenModifierDefaultLibrary = 1 << iota
+
+	// tokenModifierUnresolved marks references to unknown identifiers.
+	tokenModifierUnresolved
+)
+
+// SemanticTokenModifiers is the legend of token modifiers used by
+// SemanticTokens, as a bit set.
+var SemanticTokenModifiers = []string{
+	string(protocol.SemanticTokenModifierDefaultLibrary),
+	"unresolved",
+}
+
+// semanticTokenType returns the token type of the name of identifiers of
+// the kind.
+func (k identifierKind) semanticTokenType() semanticTokenType {
+	switch k {
+	case IdentKindParam:
+		return tokenParameter
+	case IdentKindParamProperty, IdentKindResult, IdentKindStepResult:
+		return tokenProperty
+	case IdentKindWorkspace:
+		return tokenVariable
+	case IdentKindPipelineTask, IdentKindTask, IdentKindPipeline, IdentKindStep, IdentKindSidecar:
+		return tokenFunction
+	case IdentKindContext:
+		return tokenMacro
+	}
+	return tokenVariable
+}
+
+// semanticToken is a classified fragment of a document, as [start, end)
+// offsets.
+type semanticToken struct {
+	start     int
+	end       int
+	typ       semanticTokenType
+	modifiers int
+}
+
+// semanticTokens classifies each segment of the variable substitutions in
+// the document: the leading prefix, as `params`, the names of identifiers
+// referred to, and any other accessor, such as `path`.
+func (d *Document) semanticTokens() []semanticToken {
+	var res []semanticToken
+	for _, s := range d.substitutions {
+		for i, seg := range s.segments {
+			tok := semanticToken{
+				start: seg.start,
+				end:   seg.end,
+				typ:   tokenKeyword,
+			}
+			if i == 0 {
+				tok.typ = tokenNamespace
+			}
+			if ref := d.substitutionReference(seg); ref != nil {
+				tok.typ = ref.kind.semanticTokenType()
+				if ref.ident == nil {
+					tok.modifiers |= tokenModifierUnresolved
+				} else if ref.ident.builtin {
+					tok.modifiers |= tokenModifierDefaultLibrary
+				}
+			}
+			res = append(res, tok)
+		}
+	}
+	return res
+}
+
+// substitutionReference returns the reference whose name contains the given
+// substitution segment, or nil if there is none. If several references
+// contain it, as the names of context variables, the narrowest is returned.
+func (d *Document) substitutionReference(seg segment) *reference {
+	var res *reference
+	for i, ref := range d.references {
+		if !ref.substitution || len(ref.offsets) < 4 {
+			continue
+		}
+		if seg.start < ref.offsets[2] || seg.end > ref.offsets[3] {
+			continue
+		}
+		if res != nil && res.offsets[3]-res.offsets[2] <= ref.offsets[3]-ref.offsets[2] {
+			continue
+		}
+		res = &d.references[i]
+	}
+	return res
+}
+
+// SemanticTokens returns the semantic tokens of the file, encoded as
+// relative positions as defined by the LSP specification. If r is not nil,
+// only tokens starting within the range are returned.
+func (f *File) SemanticTokens(r *protocol.Range) []protocol.UInteger {
+	data := []protocol.UInteger{}
+	if f.parseError != nil {
+		return data
+	}
+
+	var tokens []semanticToken
+	for _, d := range f.docs {
+		tokens = append(tokens, d.semanticTokens()...)
+	}
+	slices.SortFunc(tokens, func(a, b semanticToken) int {
+		return a.start - b.start
+	})
+
+	var prev protocol.Position
+	for _, t := range tokens {
+		start := f.OffsetPosition(t.start)
+		if r != nil && !inRange(start, *r) {
+			continue
+		}
+
+		deltaStart := start.Character
+		if start.Line == prev.Line {
+			deltaStart -= prev.Character
+		}
+		data = append(
+			data,
+			start.Line-prev.Line,
+			deltaStart,
+			protocol.UInteger(t.end-t.start),
+			protocol.UInteger(t.typ),
+			protocol.UInteger(t.modifiers),
+		)
+		prev = start
+	}
+	return data
+}
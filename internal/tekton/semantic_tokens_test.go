@@ -0,0 +1,57 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestSemanticTokens(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  params:
+  - name: foo
+  workspaces:
+  - name: source
+  steps:
+  - name: echo
+    image: busybox
+    script: |
+      echo $(params.foo) $(params.bar)
+      ls $(workspaces.source.path) $(context.task.name)
+`))
+
+	want := []protocol.UInteger{
+		// params.foo
+		13, 13, 6, uint32(tokenNamespace), 0,
+		0, 7, 3, uint32(tokenParameter), 0,
+		// params.bar
+		0, 7, 6, uint32(tokenNamespace), 0,
+		0, 7, 3, uint32(tokenParameter), tokenModifierUnresolved,
+		// workspaces.source.path
+		1, 11, 10, uint32(tokenNamespace), 0,
+		0, 11, 6, uint32(tokenVariable), 0,
+		0, 7, 4, uint32(tokenKeyword), 0,
+		// context.task.name
+		0, 8, 7, uint32(tokenNamespace), 0,
+		0, 8, 4, uint32(tokenMacro), tokenModifierDefaultLibrary,
+		0, 5, 4, uint32(tokenMacro), tokenModifierDefaultLibrary,
+	}
+	if got := f.SemanticTokens(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("SemanticTokens:\ngot  %v\nwant %v", got, want)
+	}
+
+	got := f.SemanticTokens(&protocol.Range{
+		Start: protocol.Position{Line: 14, Character: 0},
+		End:   protocol.Position{Line: 15, Character: 0},
+	})
+	want = append([]protocol.UInteger{14, 11}, want[22:]...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SemanticTokens(range):\ngot  %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,329 @@
+package tekton
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// snippet is a scaffold of Tekton YAML suggested as an LSP snippet.
+type snippet struct {
+	label       string
+	description string
+
+	// body is the LSP snippet text. Lines after the first one are indented
+	// to the column in which the snippet is inserted.
+	body string
+
+	// paths is the list of paths, as returned by indentPath, in which the
+	// snippet is suggested.
+	paths []string
+
+	// kind, if set, restricts the snippet to documents of the given
+	// lowercase kind. If "-", the snippet is suggested only in documents
+	// without a kind.
+	kind string
+
+	// listItem makes the snippet be suggested as a new item of the list in
+	// paths, after a `- `. Otherwise, it's suggested at the start of a line,
+	// as a new key of the mapping in paths.
+	listItem bool
+}
+
+var _ Meta = &snippet{}
+
+func (s *snippet) Completions(_ *Document) []completion {
+	return []completion{}
+}
+
+func (s *snippet) Name() string {
+	return s.label
+}
+
+func (s *snippet) Documentation() string {
+	return fmt.Sprintf("%s\n\n```yaml\n%s\n```", s.description, snippetText(s.body))
+}
+
+// snippets is the list of scaffolds suggested regardless of the workspace
+// contents.
+var snippets = []*snippet{
+	{
+		label:       "Task",
+		description: "A new Task document.",
+		body: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: ${1:name}
+spec:
+  params:
+  - name: ${2:param}
+  steps:
+  - name: ${3:step}
+    image: ${4:busybox}
+    script: |
+      ${0}`,
+		paths: []string{"$"},
+		kind:  "-",
+	},
+	{
+		label:       "step",
+		description: "A step running a script.",
+		body: `name: ${1:step}
+image: ${2:busybox}
+script: |
+  ${0}`,
+		paths: []string{
+			"$.spec.steps",
+			"$.spec.tasks[*].taskSpec.steps",
+			"$.spec.finally[*].taskSpec.steps",
+		},
+		listItem: true,
+	},
+	{
+		label:       "when",
+		description: "A when expression guarding the pipeline task.",
+		body: `when:
+- input: ${1}
+  operator: ${2|in,notin|}
+  values:
+  - ${3}`,
+		paths: []string{"$.spec.tasks[*]", "$.spec.finally[*]"},
+		kind:  "pipeline",
+	},
+	{
+		label:       "finally",
+		description: "Pipeline tasks which run after all other tasks.",
+		body: `finally:
+- name: ${1:cleanup}
+  taskRef:
+    name: ${2}`,
+		paths: []string{"$.spec"},
+		kind:  "pipeline",
+	},
+}
+
+// snippetPlaceholderRegexp matches snippet tabstops and placeholders, such
+// as $0, ${1}, ${1:default} or ${1|a,b|}.
+var snippetPlaceholderRegexp = regexp.MustCompile(`\$(?:\d+|\{\d+(?::([^}]*)|\|([^,|]*)[^|]*\|)?\})`)
+
+// snippetText returns the snippet body with every placeholder replaced by
+// its default text, or its first choice.
+func snippetText(body string) string {
+	return snippetPlaceholderRegexp.ReplaceAllString(body, "$1$2")
+}
+
+// snippetEscape escapes text to be inserted verbatim in a snippet.
+func snippetEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`).Replace(s)
+}
+
+// pipelineTaskSnippet returns a snippet adding a pipeline task which runs
+// the given Task, passing the parameters and workspaces it requires.
+func (w *Workspace) pipelineTaskSnippet(task string) *snippet {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: ${1:%s}\ntaskRef:\n  name: %s", snippetEscape(task), snippetEscape(task))
+
+	tabstop := 2
+	var params []string
+	for _, id := range w.getIdents(&taskParamsLocator{taskName: task}) {
+		p, ok := id.meta.(*identParam)
+		if !ok || p.HasDefault() {
+			continue
+		}
+		value := fmt.Sprintf("${%d}", tabstop)
+		switch p.Type() {
+		case "array":
+			value = fmt.Sprintf("[${%d}]", tabstop)
+		case "object":
+			value = fmt.Sprintf("{${%d}}", tabstop)
+		}
+		params = append(params, fmt.Sprintf("\n- name: %s\n  value: %s", snippetEscape(p.Name()), value))
+		tabstop++
+	}
+	if len(params) > 0 {
+		b.WriteString("\nparams:" + strings.Join(params, ""))
+	}
+
+	var workspaces []string
+	for _, id := range w.getIdents(&taskWorkspacesLocator{taskName: task}) {
+		ws, ok := id.meta.(IdentWorkspace)
+		if !ok || ws.Optional() {
+			continue
+		}
+		workspaces = append(workspaces, fmt.Sprintf(
+			"\n- name: %s\n  workspace: ${%d:%s}",
+			snippetEscape(ws.Name()),
+			tabstop,
+			snippetEscape(ws.Name()),
+		))
+		tabstop++
+	}
+	if len(workspaces) > 0 {
+		b.WriteString("\nworkspaces:" + strings.Join(workspaces, ""))
+	}
+
+	return &snippet{
+		label:       "task " + task,
+		description: fmt.Sprintf("A pipeline task running the Task %s.", task),
+		body:        b.String(),
+		paths:       []string{"$.spec.tasks", "$.spec.finally"},
+		kind:        "pipeline",
+		listItem:    true,
+	}
+}
+
+// workspaceSnippets returns the snippets built from the workspace contents,
+// such as a pipeline task for each Task.
+func (w *Workspace) workspaceSnippets() []*snippet {
+	if w == nil {
+		return nil
+	}
+	var tasks []string
+	for _, id := range w.getIdents(&kindLocator{IdentKindTask}) {
+		if !slices.Contains(tasks, id.meta.Name()) {
+			tasks = append(tasks, id.meta.Name())
+		}
+	}
+	slices.Sort(tasks)
+
+	var res []*snippet
+	for _, t := range tasks {
+		res = append(res, w.pipelineTaskSnippet(t))
+	}
+	return res
+}
+
+// snippetCompletions returns the snippets which can be inserted in the given
+// position.
+func (f *File) snippetCompletions(pos protocol.Position) []fmt.Stringer {
+	res := []fmt.Stringer{}
+
+	line := f.GetLine(pos.Line)
+	start := f.CompletionStart(pos)
+	prefix := strings.TrimSpace(line[:start])
+	path := f.indentPath(pos)
+	kind := f.indentKind(pos)
+	indent := strings.Repeat(" ", start)
+
+	for _, s := range slices.Concat(snippets, f.workspace.workspaceSnippets()) {
+		want := ""
+		if s.listItem {
+			want = "-"
+		}
+		if prefix != want || !slices.Contains(s.paths, path) {
+			continue
+		}
+		switch s.kind {
+		case "":
+		case "-":
+			if kind != "" {
+				continue
+			}
+		default:
+			if kind != s.kind {
+				continue
+			}
+		}
+		res = append(res, CompletionCandidate{
+			Text:    s.label,
+			Snippet: strings.ReplaceAll(s.body, "\n", "\n"+indent),
+			Value:   s,
+			Kind:    protocol.CompletionItemKindSnippet,
+			Detail:  s.description,
+		})
+	}
+	return res
+}
+
+// lineIndent returns the indentation of a line and its contents, or false if
+// the line is blank or a comment.
+func lineIndent(line string) (int, string, bool) {
+	content := strings.TrimLeft(line, " ")
+	if content == "" || strings.HasPrefix(content, "#") {
+		return 0, "", false
+	}
+	return len(line) - len(content), strings.TrimRight(content, " "), true
+}
+
+// indentPath returns the path of the YAML node enclosing the word being
+// completed in the given position, as in `$.spec.tasks[*]`. It's found from
+// the indentation of the previous lines, since the document can't be parsed
+// into the expected structure while a new key or list item is being typed.
+// Sequence items are always matched by `[*]`.
+func (f *File) indentPath(pos protocol.Position) string {
+	line := f.GetLine(pos.Line)
+	start := f.CompletionStart(pos)
+
+	// keys and list items indented less than these limits enclose the
+	// position
+	keyLimit, itemLimit := start, start
+	if dash := strings.LastIndex(line[:start], "-"); dash != -1 &&
+		strings.TrimSpace(line[:start]) == "-" {
+		// a new item, which may be in a list as indented as its key
+		keyLimit, itemLimit = dash+1, dash
+	}
+
+	var segments []string
+	for l := int(pos.Line) - 1; l >= 0 && keyLimit > 0; l-- {
+		text := f.GetLine(uint32(l))
+		if strings.HasPrefix(text, "---") {
+			break
+		}
+		indent, content, ok := lineIndent(text)
+		if !ok {
+			continue
+		}
+
+		if strings.HasPrefix(content, "- ") || content == "-" {
+			if indent >= itemLimit {
+				continue
+			}
+			// an item whose first key opens a nested block, as in
+			// `- taskSpec:`, encloses the position within that key
+			if key, ok := strings.CutSuffix(content[1:], ":"); ok &&
+				indent+2 < keyLimit && !strings.ContainsAny(key, ":") {
+				segments = append(segments, "."+strings.TrimSpace(key))
+			}
+			segments = append(segments, "[*]")
+			keyLimit, itemLimit = indent+1, indent
+			continue
+		}
+
+		if indent >= keyLimit {
+			continue
+		}
+		key, ok := strings.CutSuffix(content, ":")
+		if !ok || strings.ContainsAny(key, ":") {
+			// a scalar value can't enclose the position
+			continue
+		}
+		segments = append(segments, "."+key)
+		keyLimit, itemLimit = indent, indent
+	}
+
+	slices.Reverse(segments)
+	return "$" + strings.Join(segments, "")
+}
+
+// indentKind returns the lowercase kind of the YAML document, delimited by
+// `---` separators, which contains the given position. Unlike
+// Document.kind, it doesn't require the document to be parsed.
+func (f *File) indentKind(pos protocol.Position) string {
+	lines := strings.Split(string(f.TextDocument), "\n")
+	first := min(int(pos.Line), len(lines))
+	for first > 0 && !strings.HasPrefix(lines[first-1], "---") {
+		first--
+	}
+	for _, line := range lines[first:] {
+		if strings.HasPrefix(line, "---") {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, "kind:"); ok {
+			return strings.ToLower(strings.Trim(strings.TrimSpace(v), `"'`))
+		}
+	}
+	return ""
+}
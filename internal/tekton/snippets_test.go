@@ -0,0 +1,128 @@
+package tekton
+
+import (
+	"slices"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func snippetCandidates(f *File, pos protocol.Position) map[string]string {
+	res := map[string]string{}
+	for _, c := range f.Completions(pos) {
+		if cc := c.(CompletionCandidate); cc.Snippet != "" {
+			res[cc.Text] = cc.Snippet
+		}
+	}
+	return res
+}
+
+func TestSnippets(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: revision
+    default: main
+  - name: context
+  - name: args
+    type: array
+  workspaces:
+  - name: source
+  - name: cache
+    optional: true
+  steps:
+  - name: build
+    image: busybox
+  - st
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: first
+    taskRef:
+      name: build
+    wh
+  - bu
+  fin
+`)
+	w.UpsertFile("file://new.yaml", `Ta`)
+	w.Lint()
+
+	tcs := []struct {
+		uri  string
+		pos  protocol.Position
+		want []string
+	}{
+		{
+			uri:  "file://task.yaml",
+			pos:  protocol.Position{Line: 18, Character: 6},
+			want: []string{"step"},
+		},
+		{
+			uri:  "file://task.yaml",
+			pos:  protocol.Position{Line: 17, Character: 10},
+			want: nil,
+		},
+		{
+			uri:  "file://pipe.yaml",
+			pos:  protocol.Position{Line: 9, Character: 6},
+			want: []string{"when"},
+		},
+		{
+			uri:  "file://pipe.yaml",
+			pos:  protocol.Position{Line: 10, Character: 6},
+			want: []string{"task build"},
+		},
+		{
+			uri:  "file://pipe.yaml",
+			pos:  protocol.Position{Line: 11, Character: 5},
+			want: []string{"finally"},
+		},
+		{
+			uri:  "file://new.yaml",
+			pos:  protocol.Position{Line: 0, Character: 2},
+			want: []string{"Task"},
+		},
+	}
+	for _, tc := range tcs {
+		var got []string
+		for label := range snippetCandidates(w.File(tc.uri), tc.pos) {
+			got = append(got, label)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("Snippets(%s, %v): got %v, want %v", tc.uri, tc.pos, got, tc.want)
+		}
+	}
+
+	got := snippetCandidates(w.File("file://pipe.yaml"), protocol.Position{Line: 10, Character: 6})["task build"]
+	want := `name: ${1:build}
+    taskRef:
+      name: build
+    params:
+    - name: context
+      value: ${2}
+    - name: args
+      value: [${3}]
+    workspaces:
+    - name: source
+      workspace: ${4:source}`
+	if got != want {
+		t.Errorf("pipeline task snippet:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSnippetText(t *testing.T) {
+	got := snippetText("name: ${1:step}\noperator: ${2|in,notin|}\nvalue: ${3}$0")
+	want := "name: step\noperator: in\nvalue: "
+	if got != want {
+		t.Errorf("snippetText: got %q, want %q", got, want)
+	}
+}
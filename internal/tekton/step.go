@@ -0,0 +1,137 @@
+package tekton
+
+import (
+	"fmt"
+
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+type identStep struct {
+	value StringMap
+
+	// node is the YAML node containing the whole step definition.
+	node ast.Node
+}
+
+func IdentStep(v StringMap, node ast.Node) *identStep {
+	return &identStep{
+		value: v,
+		node:  node,
+	}
+}
+
+var _ Meta = &identStep{}
+
+func (s *identStep) Completions(_ *Document) []completion {
+	return []completion{
+		{
+			text: fmt.Sprintf("$(steps.%s.exitCode.path)", s.Name()),
+		},
+	}
+}
+
+func (s *identStep) Name() string {
+	n, _ := s.value["name"].(string)
+	return n
+}
+
+func (s *identStep) Image() string {
+	i, _ := s.value["image"].(string)
+	return i
+}
+
+func (s *identStep) Documentation() string {
+	return fmt.Sprintf("```yaml\nname: %s\nimage: %s\n```", s.Name(), s.Image())
+}
+
+// contains returns true if the given offset of the document lies within
+// the step definition.
+func (s *identStep) contains(d *Document, offset int) bool {
+	pos := d.OffsetPosition(offset)
+	return yaml_helper.FindNode(s.node, int(pos.Line)+1, int(pos.Character)+1) != nil
+}
+
+// stepResult is a result declared by a step, written through
+// $(step.results.name.path).
+type stepResult struct {
+	value StringMap
+	step  string
+}
+
+func StepResult(v StringMap, step string) *stepResult {
+	return &stepResult{
+		value: v,
+		step:  step,
+	}
+}
+
+var _ Meta = &stepResult{}
+
+func (r *stepResult) Completions(_ *Document) []completion {
+	return []completion{
+		{
+			text: fmt.Sprintf("$(steps.%s.results.%s.path)", r.step, r.Name()),
+		},
+	}
+}
+
+func (r *stepResult) Name() string {
+	n, _ := r.value["name"].(string)
+	return n
+}
+
+func (r *stepResult) Type() string {
+	if t, ok := r.value["type"].(string); ok {
+		return t
+	}
+	return "string"
+}
+
+func (r *stepResult) Description() string {
+	d, _ := r.value["description"].(string)
+	return d
+}
+
+func (r *stepResult) Documentation() string {
+	return fmt.Sprintf(
+		"```yaml\nstep: %s\nname: %s\ntype: %s\n%s\n```",
+		r.step,
+		r.Name(),
+		r.Type(),
+		r.Description(),
+	)
+}
+
+// stepResultLocator locates a step result given its name and the name of
+// the step which declares it.
+type stepResultLocator struct {
+	name string
+	step string
+}
+
+func (l *stepResultLocator) matches(id *identifier) bool {
+	if id.kind != IdentKindStepResult {
+		return false
+	}
+	r, ok := id.meta.(*stepResult)
+	if !ok {
+		return false
+	}
+	return r.Name() == l.name && r.step == l.step
+}
+
+// enclosingStep returns the step which contains the given document offset,
+// or nil if there is none.
+func (d *Document) enclosingStep(offset int) *identStep {
+	for _, id := range d.identifiers {
+		s, ok := id.meta.(*identStep)
+		if !ok || id.kind != IdentKindStep {
+			continue
+		}
+		if s.contains(d, offset) {
+			return s
+		}
+	}
+	return nil
+}
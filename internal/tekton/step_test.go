@@ -0,0 +1,109 @@
+package tekton
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+const stepsDoc = `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: digest
+    value: $(steps.build.results.digest)
+  sidecars:
+  - name: registry
+    image: registry
+  steps:
+  - name: build
+    image: kaniko
+    results:
+    - name: digest
+    - name: digest
+    - name: tags
+      type: list
+    script: |
+      echo sha > $(step.results.digest.path)
+      echo [] > $(step.results.tags.path)
+  - name: check
+    image: busybox
+    script: |
+      test $(cat $(steps.build.exitCode.path)) = 0
+      cat $(steps.build.results.unknown.path) $(steps.push.exitCode.path)
+  - name: check
+    image: busybox
+`
+
+func TestSteps(t *testing.T) {
+	f := parseFile(file.TextDocument(stepsDoc))
+	d := f.docs[0]
+
+	got := diagnosticMessages(f)
+	slices.Sort(got)
+	want := []string{
+		"duplicate step check",
+		"duplicate stepResult digest",
+		"step result tags has unknown type list",
+		"unknown step push",
+		"unknown stepResult unknown",
+		"unused stepResult digest",
+		"unused task build",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	step := d.getIdent(&kindNameLocator{IdentKindStep, "build"})
+	if step == nil {
+		t.Fatalf("step build not found")
+	}
+	wantRefs := []protocol.Range{
+		{
+			Start: protocol.Position{Line: 7, Character: 11},
+			End:   protocol.Position{Line: 7, Character: 40},
+		},
+		{
+			Start: protocol.Position{Line: 25, Character: 17},
+			End:   protocol.Position{Line: 25, Character: 45},
+		},
+		{
+			Start: protocol.Position{Line: 26, Character: 10},
+			End:   protocol.Position{Line: 26, Character: 45},
+		},
+	}
+	if got := locationToRange(wholeReferences(step)); !reflect.DeepEqual(got, wantRefs) {
+		t.Errorf("step references:\ngot %v\nwant %v", got, wantRefs)
+	}
+
+	result := d.getIdent(&stepResultLocator{name: "digest", step: "build"})
+	if result == nil {
+		t.Fatalf("step result digest not found")
+	}
+	wantRefs = []protocol.Range{
+		{
+			Start: protocol.Position{Line: 7, Character: 33},
+			End:   protocol.Position{Line: 7, Character: 39},
+		},
+		{
+			Start: protocol.Position{Line: 20, Character: 32},
+			End:   protocol.Position{Line: 20, Character: 38},
+		},
+	}
+	if got := locationToRange(wholeReferences(result)); !reflect.DeepEqual(got, wantRefs) {
+		t.Errorf("step result references:\ngot %v\nwant %v", got, wantRefs)
+	}
+
+	edit, err := f.Rename(protocol.Position{Line: 12, Character: 10}, "compile")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if got := len(edit.Changes[f.uri]); got != 4 {
+		t.Errorf("Rename: got %d edits, want 4", got)
+	}
+}
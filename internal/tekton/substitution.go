@@ -0,0 +1,180 @@
+package tekton
+
+import "strings"
+
+// substitution is a Tekton variable substitution expression, such as
+// $(params.name), $(params["my.param"]) or $(tasks.a.results.r[1]).
+type substitution struct {
+	// start and end are the [start, end) offsets of the whole expression,
+	// from `$(` up to and including `)`.
+	start int
+	end   int
+
+	// segments is the list of path segments of the expression, in order.
+	segments []segment
+}
+
+// segment is a single path segment of a substitution expression. It is
+// either a name, as `params` and `name` in $(params.name), a quoted name,
+// as `my.param` in $(params["my.param"]), or an index, as `0` and `*` in
+// $(params.list[0]) and $(params.list[*]).
+type segment struct {
+	// value is the unquoted text of the segment.
+	value string
+
+	// start and end are the [start, end) offsets of value, excluding any
+	// brackets or quotes.
+	start int
+	end   int
+
+	// index is true if the segment is a bracket index such as [0] or [*].
+	index bool
+}
+
+// names returns the leading name segments of the substitution, stopping at
+// the first index segment.
+func (s substitution) names() []segment {
+	for i, seg := range s.segments {
+		if seg.index {
+			return s.segments[:i]
+		}
+	}
+	return s.segments
+}
+
+// accessor returns the canonical text of the segments following the i-th
+// segment, such as `.path` or `[*]`.
+func (s substitution) accessor(i int) string {
+	var sb strings.Builder
+	for _, seg := range s.segments[i+1:] {
+		if seg.index {
+			sb.WriteString("[" + seg.value + "]")
+		} else {
+			sb.WriteString("." + seg.value)
+		}
+	}
+	return sb.String()
+}
+
+// isNameChar reports whether c may be part of an unquoted segment name.
+func isNameChar(c byte) bool {
+	switch c {
+	case '.', '[', ']', '(', ')', '$', '"', '\'', ' ', '\t', '\r', '\n':
+		return false
+	}
+	return true
+}
+
+// parseSubstitutions returns every well-formed substitution expression in
+// text. Offsets are shifted by base, so that they are relative to the
+// beginning of the containing file. Malformed expressions are skipped.
+func parseSubstitutions(text []byte, base int) []substitution {
+	var subs []substitution
+	for i := 0; i+1 < len(text); i++ {
+		if text[i] != '$' || text[i+1] != '(' {
+			continue
+		}
+		s, ok := parseSubstitution(text, i)
+		if !ok {
+			continue
+		}
+		s.start += base
+		s.end += base
+		for j := range s.segments {
+			s.segments[j].start += base
+			s.segments[j].end += base
+		}
+		subs = append(subs, s)
+		i = s.end - base - 1
+	}
+	return subs
+}
+
+// parseSubstitution parses the substitution expression starting at the
+// `$(` found in text[start:].
+func parseSubstitution(text []byte, start int) (substitution, bool) {
+	s := substitution{start: start}
+	i := start + 2
+
+	// name consumes an unquoted name segment starting at i.
+	name := func() bool {
+		j := i
+		for j < len(text) && isNameChar(text[j]) {
+			j++
+		}
+		if j == i {
+			return false
+		}
+		s.segments = append(s.segments, segment{
+			value: string(text[i:j]),
+			start: i,
+			end:   j,
+		})
+		i = j
+		return true
+	}
+
+	// bracket consumes a bracket segment, either quoted or an index,
+	// starting right after the `[` at i-1.
+	bracket := func() bool {
+		if i >= len(text) {
+			return false
+		}
+		if q := text[i]; q == '"' || q == '\'' {
+			j := i + 1
+			for j < len(text) && text[j] != q && text[j] != '\n' {
+				j++
+			}
+			if j+1 >= len(text) || text[j] != q || text[j+1] != ']' {
+				return false
+			}
+			s.segments = append(s.segments, segment{
+				value: string(text[i+1 : j]),
+				start: i + 1,
+				end:   j,
+			})
+			i = j + 2
+			return true
+		}
+
+		j := i
+		for j < len(text) && (text[j] == '*' || (text[j] >= '0' && text[j] <= '9')) {
+			j++
+		}
+		if j == i || j >= len(text) || text[j] != ']' {
+			return false
+		}
+		s.segments = append(s.segments, segment{
+			value: string(text[i:j]),
+			start: i,
+			end:   j,
+			index: true,
+		})
+		i = j + 1
+		return true
+	}
+
+	if !name() {
+		return s, false
+	}
+	for i < len(text) {
+		switch text[i] {
+		case ')':
+			s.end = i + 1
+			return s, true
+		case '.':
+			i++
+			if !name() {
+				return s, false
+			}
+		case '[':
+			i++
+			if !bracket() {
+				return s, false
+			}
+		default:
+			return s, false
+		}
+	}
+	return s, false
+}
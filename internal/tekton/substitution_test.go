@@ -0,0 +1,176 @@
+package tekton
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestParseSubstitutions(t *testing.T) {
+	tcs := []struct {
+		text string
+		want []substitution
+	}{
+		{
+			text: "echo $(params.foo)",
+			want: []substitution{
+				{
+					start: 5,
+					end:   18,
+					segments: []segment{
+						{value: "params", start: 7, end: 13},
+						{value: "foo", start: 14, end: 17},
+					},
+				},
+			},
+		},
+		{
+			text: `$(params["my.param"]) $(params['x'])`,
+			want: []substitution{
+				{
+					start: 0,
+					end:   21,
+					segments: []segment{
+						{value: "params", start: 2, end: 8},
+						{value: "my.param", start: 10, end: 18},
+					},
+				},
+				{
+					start: 22,
+					end:   36,
+					segments: []segment{
+						{value: "params", start: 24, end: 30},
+						{value: "x", start: 32, end: 33},
+					},
+				},
+			},
+		},
+		{
+			text: "$(tasks.a.results.r[1])$(params.list[*])",
+			want: []substitution{
+				{
+					start: 0,
+					end:   23,
+					segments: []segment{
+						{value: "tasks", start: 2, end: 7},
+						{value: "a", start: 8, end: 9},
+						{value: "results", start: 10, end: 17},
+						{value: "r", start: 18, end: 19},
+						{value: "1", start: 20, end: 21, index: true},
+					},
+				},
+				{
+					start: 23,
+					end:   40,
+					segments: []segment{
+						{value: "params", start: 25, end: 31},
+						{value: "list", start: 32, end: 36},
+						{value: "*", start: 37, end: 38, index: true},
+					},
+				},
+			},
+		},
+		{
+			// malformed expressions are skipped
+			text: `$(params.) $(params["x) $(params.a b) $(params.x[y]) $(params.ok`,
+			want: nil,
+		},
+		{
+			text: "$($(params.nested))",
+			want: []substitution{
+				{
+					start: 2,
+					end:   18,
+					segments: []segment{
+						{value: "params", start: 4, end: 10},
+						{value: "nested", start: 11, end: 17},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		got := parseSubstitutions([]byte(tc.text), 0)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseSubstitutions(%q):\ngot %+v\nwant %+v", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestSubstitutionReferences(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  params:
+  - name: my.param
+  - name: list
+    type: array
+  steps:
+  - name: echo
+    image: busybox
+    script: |
+      echo $(params["my.param"]) $(params['list'][0]) $(params.list[1])
+`))
+	d := f.docs[0]
+
+	tcs := []struct {
+		name string
+		refs []protocol.Range
+	}{
+		{
+			name: "my.param",
+			refs: []protocol.Range{
+				{
+					Start: protocol.Position{Line: 13, Character: 11},
+					End:   protocol.Position{Line: 13, Character: 32},
+				},
+			},
+		},
+		{
+			name: "list",
+			refs: []protocol.Range{
+				{
+					Start: protocol.Position{Line: 13, Character: 33},
+					End:   protocol.Position{Line: 13, Character: 53},
+				},
+				{
+					Start: protocol.Position{Line: 13, Character: 54},
+					End:   protocol.Position{Line: 13, Character: 71},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		id := d.getIdent(&kindNameLocator{IdentKindParam, tc.name})
+		if id == nil {
+			t.Fatalf("param %s not found", tc.name)
+		}
+		got := locationToRange(wholeReferences(id))
+		if !reflect.DeepEqual(got, tc.refs) {
+			t.Errorf("references of %s:\ngot %v\nwant %v", tc.name, got, tc.refs)
+		}
+	}
+
+	// renaming only edits the name segment, leaving quotes in place
+	edit, err := f.Rename(protocol.Position{Line: 6, Character: 10}, "other")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	want := protocol.Range{
+		Start: protocol.Position{Line: 13, Character: 21},
+		End:   protocol.Position{Line: 13, Character: 29},
+	}
+	if got := edit.Changes[f.uri][1].Range; got != want {
+		t.Errorf("Rename: got %v, want %v", got, want)
+	}
+
+	wantMsgs := []string{"unused task hello"}
+	if got := diagnosticMessages(f); !reflect.DeepEqual(got, wantMsgs) {
+		t.Errorf("Diagnostics: got %v, want %v", got, wantMsgs)
+	}
+}
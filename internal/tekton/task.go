@@ -2,9 +2,9 @@ package tekton
 
 type IdentTask StringMap
 
-var _ Meta = PipelineTask{}
+var _ Meta = IdentTask{}
 
-func (p IdentTask) Completions() []completion {
+func (p IdentTask) Completions(_ *Document) []completion {
 	return []completion{}
 }
 
@@ -17,6 +17,31 @@ func (p IdentTask) Name() string {
 	return n
 }
 
+// Results returns the results declared in the Task spec.
+func (p IdentTask) Results() []IdentResult {
+	return specResults(StringMap(p)["spec"])
+}
+
 func (p IdentTask) Documentation() string {
 	return ""
 }
+
+// specResults returns the list of results declared in the `results` property
+// of a Task spec, or embedded Task spec.
+func specResults(spec interface{}) []IdentResult {
+	sm, ok := spec.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	rs, _ := sm["results"].([]interface{})
+
+	var res []IdentResult
+	for _, r := range rs {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res = append(res, IdentResult(rm))
+	}
+	return res
+}
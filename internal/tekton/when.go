@@ -0,0 +1,279 @@
+package tekton
+
+import (
+	"fmt"
+
+	yaml_helper "github.com/cezarguimaraes/tekton-ls/internal/yaml"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// whenOperator is an operator of a when expression.
+type whenOperator string
+
+var _ Meta = whenOperator("")
+
+// whenOperators is the list of operators accepted by when expressions.
+var whenOperators = []whenOperator{"in", "notin"}
+
+func (o whenOperator) Completions(_ *Document) []completion {
+	return []completion{
+		{
+			text: string(o),
+		},
+	}
+}
+
+func (o whenOperator) Name() string {
+	return string(o)
+}
+
+func (o whenOperator) Documentation() string {
+	if o == "notin" {
+		return "`notin`: the task runs if `input` is not any of `values`."
+	}
+	return "`in`: the task runs if `input` is one of `values`."
+}
+
+// validWhenOperator returns true if the given operator is accepted by when
+// expressions.
+func validWhenOperator(op string) bool {
+	for _, o := range whenOperators {
+		if string(o) == op {
+			return true
+		}
+	}
+	return false
+}
+
+// resultDependencies returns the names of the pipeline tasks whose results
+// are consumed through substitutions in any string within v.
+func resultDependencies(v interface{}) []string {
+	var deps []string
+	switch t := v.(type) {
+	case string:
+		for _, s := range parseSubstitutions([]byte(t), 0) {
+			names := s.names()
+			if len(names) >= 4 && names[0].value == "tasks" && names[2].value == "results" {
+				deps = append(deps, names[1].value)
+			}
+		}
+	case []interface{}:
+		for _, e := range t {
+			deps = append(deps, resultDependencies(e)...)
+		}
+	case map[string]interface{}:
+		for _, e := range t {
+			deps = append(deps, resultDependencies(e)...)
+		}
+	}
+	return deps
+}
+
+// directDependencies returns the names of the pipeline tasks this task runs
+// after, either explicitly through `runAfter` or implicitly by consuming
+// their results in its parameters, matrix or when expressions.
+func (p *pipelineTask) directDependencies() []string {
+	var deps []string
+	ra, _ := p.value["runAfter"].([]interface{})
+	for _, v := range ra {
+		if s, ok := v.(string); ok {
+			deps = append(deps, s)
+		}
+	}
+	deps = append(deps, resultDependencies(p.value["params"])...)
+	deps = append(deps, resultDependencies(p.value["matrix"])...)
+	deps = append(deps, resultDependencies(p.value["when"])...)
+	return deps
+}
+
+// dependencies returns the set of pipeline tasks in the document which run
+// before the given pipeline task, transitively.
+func (d *Document) dependencies(pt *pipelineTask) map[string]struct{} {
+	deps := map[string]struct{}{}
+	queue := pt.directDependencies()
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := deps[name]; ok {
+			continue
+		}
+		deps[name] = struct{}{}
+
+		id := d.getIdent(&kindNameLocator{IdentKindPipelineTask, name})
+		if id == nil {
+			continue
+		}
+		if dep, ok := id.meta.(*pipelineTask); ok {
+			queue = append(queue, dep.directDependencies()...)
+		}
+	}
+	return deps
+}
+
+// whenDependencyProblem returns why the pipeline task named dep, whose
+// results are consumed by the when expressions of pt, can't be one of its
+// dependencies, or "" if it can.
+func (d *Document) whenDependencyProblem(pt *pipelineTask, dep string, finally map[string]struct{}) string {
+	if dep == pt.Name() {
+		return "it can't consume its own results"
+	}
+	if _, ok := finally[dep]; ok {
+		return "it is a finally task"
+	}
+	id := d.getIdent(&kindNameLocator{IdentKindPipelineTask, dep})
+	if id == nil {
+		return ""
+	}
+	if other, ok := id.meta.(*pipelineTask); ok {
+		if _, ok := d.dependencies(other)[pt.Name()]; ok {
+			return "it runs after it"
+		}
+	}
+	return ""
+}
+
+// whenDiagnostics reports when expressions of pipeline tasks with unknown
+// operators, mixing `cel` with `input`, `operator` and `values`, or which
+// consume results of tasks which can't be dependencies of the pipeline task.
+func (d *Document) whenDiagnostics(c chan<- *protocol.Diagnostic) {
+	finally := map[string]struct{}{}
+	yaml_helper.VisitPath(d.ast.Body, []*yaml.Path{mustPathString("$.spec.finally[*]")}, func(nodes []yaml_helper.ParsedNode) {
+		if fm, ok := nodes[1].Value.(map[string]interface{}); ok {
+			if name, ok := fm["name"].(string); ok {
+				finally[name] = struct{}{}
+			}
+		}
+	})
+
+	for _, id := range d.identifiers {
+		pt, ok := id.meta.(*pipelineTask)
+		if !ok || pt.node == nil {
+			continue
+		}
+
+		// checkDeps reports results consumed by the given when expression
+		// field which can't be produced by a dependency.
+		checkDeps := func(node ast.Node, v interface{}) {
+			for _, dep := range resultDependencies(v) {
+				problem := d.whenDependencyProblem(pt, dep, finally)
+				if problem == "" {
+					continue
+				}
+				r, _ := d.getNodeRange(node)
+				c <- newDiagnostic(
+					r,
+					protocol.DiagnosticSeverityWarning,
+					"when-dependency",
+					"task %s is not a dependency of task %s, %s",
+					dep,
+					pt.Name(),
+					problem,
+				)
+			}
+		}
+
+		paths := []*yaml.Path{mustPathString("$.when[*]")}
+		yaml_helper.VisitPath(pt.node, paths, func(nodes []yaml_helper.ParsedNode) {
+			entry := nodes[1].Node
+			wm, ok := nodes[1].Value.(map[string]interface{})
+			if !ok {
+				return
+			}
+
+			for _, field := range []string{"input", "cel"} {
+				node, err := mustPathString("$." + field).FilterNode(entry)
+				if err != nil || node == nil {
+					continue
+				}
+				checkDeps(node, wm[field])
+			}
+			values := []*yaml.Path{mustPathString("$.values")}
+			yaml_helper.VisitPath(entry, values, func(vn []yaml_helper.ParsedNode) {
+				checkDeps(vn[1].Node, vn[1].Value)
+			})
+
+			if cel, ok := wm["cel"]; ok {
+				d.celDiagnostics(c, entry, wm, cel)
+				return
+			}
+
+			op := wm["operator"]
+			if op == nil {
+				// missing, or still being typed
+				return
+			}
+			if s, ok := op.(string); ok && validWhenOperator(s) {
+				return
+			}
+			node, err := mustPathString("$.operator").FilterNode(entry)
+			if err != nil || node == nil {
+				return
+			}
+			r, _ := d.getNodeRange(node)
+			c <- newDiagnostic(
+				r,
+				protocol.DiagnosticSeverityError,
+				"when-operator",
+				"unknown operator %v, must be one of %v",
+				op,
+				whenOperators,
+			)
+		})
+	}
+}
+
+// celDiagnostics reports a `cel` when expression which isn't a string, or
+// which is combined with `input`, `operator` or `values`.
+func (d *Document) celDiagnostics(c chan<- *protocol.Diagnostic, entry ast.Node, wm map[string]interface{}, cel interface{}) {
+	node, err := mustPathString("$.cel").FilterNode(entry)
+	if err != nil || node == nil {
+		return
+	}
+	r, _ := d.getNodeRange(node)
+	if _, ok := cel.(string); !ok && cel != nil {
+		c <- newDiagnostic(
+			r,
+			protocol.DiagnosticSeverityError,
+			"when-cel",
+			"cel must be a string expression",
+		)
+	}
+	for _, field := range []string{"input", "operator", "values"} {
+		if _, ok := wm[field]; !ok {
+			continue
+		}
+		c <- newDiagnostic(
+			r,
+			protocol.DiagnosticSeverityError,
+			"when-cel",
+			"cel can't be used together with %s in a when expression",
+			field,
+		)
+	}
+}
+
+// whenCompletions returns the operator completions suggested within the when
+// expressions of a pipeline task, except for CEL expressions.
+func (p *pipelineTask) whenCompletions() []completion {
+	var cs []completion
+	when, _ := p.value["when"].([]interface{})
+	for i, e := range when {
+		if wm, ok := e.(map[string]interface{}); ok {
+			if _, ok := wm["cel"]; ok {
+				continue
+			}
+		}
+		for _, o := range whenOperators {
+			cs = append(cs, completion{
+				text:    string(o),
+				context: mustPathString(fmt.Sprintf("$.when[%d]", i)),
+				scope:   p.node,
+				value:   o,
+				kind:    protocol.CompletionItemKindOperator,
+			})
+		}
+	}
+	return cs
+}
@@ -0,0 +1,162 @@
+package tekton
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cezarguimaraes/tekton-ls/internal/file"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestWhenExpressions(t *testing.T) {
+	w := NewWorkspace()
+	w.UpsertFile("file://task.yaml", `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: check
+spec:
+  results:
+  - name: changed
+  steps:
+  - name: check
+    image: busybox
+    script: echo true > $(results.changed.path)
+`)
+	w.UpsertFile("file://pipe.yaml", `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  params:
+  - name: branch
+  tasks:
+  - name: check
+    taskRef:
+      name: check
+  - name: build
+    taskRef:
+      name: check
+    runAfter: [check]
+    when:
+    - input: $(tasks.check.results.changed)
+      operator: in
+      values: ["true"]
+    - input: $(params.branch)
+      operator: equals
+      values: [main, $(tasks.check.results.missing)]
+  - name: deploy
+    taskRef:
+      name: check
+    when:
+    - input: $(tasks.check.results.changed)
+      operator: notin
+      values: ["false"]
+    - cel: "'$(tasks.build.results.changed)' == 'true'"
+    - input: $(params.branch)
+      operator: 
+`)
+	w.Lint()
+	pipe := w.File("file://pipe.yaml")
+
+	got := diagnosticMessages(pipe)
+	slices.Sort(got)
+	want := []string{
+		"unknown operator equals, must be one of [in notin]",
+		"unknown result missing",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	def := pipe.Definition(protocol.Position{Line: 16, Character: 37})
+	if def == nil || def.URI != "file://task.yaml" || def.Range.Start.Line != 6 {
+		t.Errorf("Definition: got %v, want result changed in task.yaml", def)
+	}
+
+	texts := completionTexts(pipe, protocol.Position{Line: 17, Character: 16})
+	for _, want := range []string{"in", "notin", "$(params.branch)", "$(tasks.check.results.changed)"} {
+		if !slices.Contains(texts, want) {
+			t.Errorf("Completions: %q not found in %v", want, texts)
+		}
+	}
+	texts = completionTexts(pipe, protocol.Position{Line: 29, Character: 12})
+	if !slices.Contains(texts, "$(tasks.build.results.changed)") || slices.Contains(texts, "notin") {
+		t.Errorf("Completions: got %v, want results and no operators in cel expressions", texts)
+	}
+	texts = completionTexts(pipe, protocol.Position{Line: 9, Character: 10})
+	if slices.Contains(texts, "notin") {
+		t.Errorf("Completions: unexpected operator outside of when expressions")
+	}
+}
+
+func TestPipelineTaskDependencies(t *testing.T) {
+	f := parseFile(file.TextDocument(`apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+  - name: a
+    taskRef:
+      name: task
+    when:
+    - input: $(tasks.c.results.out)
+      operator: in
+      values: ["true"]
+  - name: b
+    taskRef:
+      name: task
+    matrix:
+      params:
+      - name: p
+        value: $(tasks.a.results.out[*])
+  - name: c
+    taskRef:
+      name: task
+    runAfter: [b]
+  - name: d
+    taskRef:
+      name: task
+    when:
+    - cel: "'$(tasks.c.results.out)' == 'true'"
+    - cel: "'$(tasks.d.results.out)' == 'true'"
+    - cel: "'$(tasks.cleanup.results.out)' == 'true'"
+      operator: in
+  finally:
+  - name: cleanup
+    taskRef:
+      name: task
+`))
+
+	d := f.docs[0]
+	deps := d.dependencies(d.getIdent(&kindNameLocator{IdentKindPipelineTask, "d"}).meta.(*pipelineTask))
+	for _, name := range []string{"a", "b", "c"} {
+		if _, ok := deps[name]; !ok {
+			t.Errorf("dependencies(d): %s not found in %v", name, deps)
+		}
+	}
+
+	var got []string
+	for _, m := range diagnosticMessages(f) {
+		if !strings.HasPrefix(m, "unknown") && !strings.HasPrefix(m, "unused") {
+			got = append(got, m)
+		}
+	}
+	slices.Sort(got)
+	want := []string{
+		"cel can't be used together with operator in a when expression",
+		"task c is not a dependency of task a, it runs after it",
+		"task cleanup is not a dependency of task d, it is a finally task",
+		"task d is not a dependency of task d, it can't consume its own results",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics:\ngot %v\nwant %v", got, want)
+	}
+
+	texts := completionTexts(f, protocol.Position{Line: 28, Character: 12})
+	if slices.Contains(texts, "in") {
+		t.Errorf("Completions: unexpected operator in cel expression %v", texts)
+	}
+}
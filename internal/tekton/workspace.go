@@ -13,6 +13,9 @@ import (
 
 type Workspace struct {
 	files map[string]*File
+
+	// folders is the list of paths of the workspace folders.
+	folders []string
 }
 
 func NewWorkspace() *Workspace {
@@ -93,18 +96,16 @@ func (w *Workspace) Lint() {
 		}()
 	}
 	wg.Wait()
+	// references are solved serially, since they are added to identifiers
+	// declared in other files
 	for _, f := range w.files {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			f.solveReferences()
-		}()
+		f.solveReferences()
 	}
-	wg.Wait()
 }
 
 func (w *Workspace) AddFolder(uri string) {
 	base := strings.TrimPrefix(uri, "file://")
+	w.folders = append(w.folders, base)
 	c := make(chan *File)
 	go func() {
 		var wg sync.WaitGroup
@@ -159,6 +160,16 @@ func (w *Workspace) getIdent(l identLocator) *identifier {
 	return nil
 }
 
+// getIdents returns every identifier in the workspace matched by the given
+// locator.
+func (w *Workspace) getIdents(l identLocator) []*identifier {
+	var ids []*identifier
+	for _, f := range w.files {
+		ids = append(ids, f.getIdents(l)...)
+	}
+	return ids
+}
+
 func (w *Workspace) FindReferences(docUri string, pos protocol.Position) []protocol.Location {
 	return w.File(docUri).FindReferences(pos)
 }
@@ -171,8 +182,8 @@ func (w *Workspace) Diagnostics(cb func(protocol.PublishDiagnosticsParams)) {
 	var wg sync.WaitGroup
 	for _, f := range w.files {
 		// TODO: keep track of and include file version
+		wg.Add(1)
 		go func() {
-			wg.Add(1)
 			defer wg.Done()
 			cb(protocol.PublishDiagnosticsParams{
 				URI:         f.uri,
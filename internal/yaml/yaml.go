@@ -38,18 +38,19 @@ func FindNode(node ast.Node, line, col int) ast.Node {
 	var res ast.Node
 	// can be improved by culling the recursion
 	ast.Walk(VisitorFunc(func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
 		if _, ok := n.(*ast.NullNode); ok {
 			// workaround for tentative go-yaml bug fix
 			return false
 		}
 		tok := n.GetToken()
 		nxt := tok.Next
-		if nxt == nil {
-			return true
-		}
 
-		// tok.Position <= p && p <= nxt.Position
-		if !cmpPos(p, tok.Position) && !cmpPos(nxt.Position, p) {
+		// tok.Position <= p && p <= nxt.Position, where the last token of
+		// the document extends to its end
+		if !cmpPos(p, tok.Position) && (nxt == nil || !cmpPos(nxt.Position, p)) {
 			res = n
 			// keep iterating to find the deepest node that contains the position
 			return true
@@ -143,3 +144,104 @@ func VisitNodes(node ast.Node, depth int, f VisitFunc) {
 		VisitNodes(v, depth-1, f)
 	}
 }
+
+// MappingValues returns the list of key-value pairs of the given mapping
+// node, or nil if it isn't a mapping.
+func MappingValues(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	}
+	return nil
+}
+
+// Children returns the direct children of the given node, skipping comments
+// and null nodes.
+func Children(node ast.Node) []ast.Node {
+	var res []ast.Node
+	ast.Walk(VisitorFunc(func(n ast.Node) bool {
+		if n == node {
+			return true
+		}
+		switch n.(type) {
+		case nil, *ast.NullNode, *ast.CommentNode, *ast.CommentGroupNode:
+		default:
+			res = append(res, n)
+		}
+		return false
+	}), node)
+	return res
+}
+
+// Bounds returns the first token of the given node, including its
+// descendants, and the token following its last one, or nil if the node
+// ends the file.
+func Bounds(node ast.Node) (first *token.Token, next *token.Token) {
+	var last *token.Token
+	visit := func(tk *token.Token) {
+		if tk == nil {
+			return
+		}
+		if first == nil || cmpPos(tk.Position, first.Position) {
+			first = tk
+		}
+		if last == nil || cmpPos(last.Position, tk.Position) {
+			last = tk
+		}
+	}
+	ast.Walk(VisitorFunc(func(n ast.Node) bool {
+		switch t := n.(type) {
+		case nil, *ast.NullNode, *ast.CommentNode, *ast.CommentGroupNode:
+			return false
+		case *ast.SequenceNode:
+			// closing bracket of flow sequences
+			visit(t.End)
+		case *ast.MappingNode:
+			visit(t.End)
+		}
+		visit(n.GetToken())
+		return true
+	}), node)
+	if last != nil {
+		next = last.Next
+	}
+	return first, next
+}
+
+// FindPath returns the chain of nodes from the given node down to the
+// deepest node containing the given line and column position, that is, the
+// ancestors of the node found, followed by itself. A node contains the
+// positions from its first token up to the token following it. It returns
+// nil if the node doesn't contain the position.
+func FindPath(node ast.Node, line, col int) []ast.Node {
+	p := &token.Position{
+		Line:   line,
+		Column: col,
+	}
+	contains := func(n ast.Node) bool {
+		first, next := Bounds(n)
+		if first == nil || cmpPos(p, first.Position) {
+			return false
+		}
+		return next == nil || cmpPos(p, next.Position)
+	}
+
+	if node == nil || !contains(node) {
+		return nil
+	}
+	res := []ast.Node{node}
+	for {
+		var child ast.Node
+		for _, c := range Children(res[len(res)-1]) {
+			if contains(c) {
+				child = c
+			}
+		}
+		if child == nil {
+			return res
+		}
+		res = append(res, child)
+	}
+}
@@ -0,0 +1,44 @@
+package yaml
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml/parser"
+)
+
+func TestFindNode(t *testing.T) {
+	src := `spec:
+  name: build
+  script: echo hello`
+	f, err := parser.ParseBytes([]byte(src), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := f.Docs[0].Body
+
+	tcs := []struct {
+		name      string
+		line, col int
+		want      string
+	}{
+		{"key", 2, 3, "name"},
+		{"value", 2, 9, "build"},
+		{"between tokens", 2, 12, "build"},
+		{"last token", 3, 11, "echo hello"},
+		{"inside last token", 3, 15, "echo hello"},
+		{"after last token", 3, 30, "echo hello"},
+		{"after end of file", 5, 1, "echo hello"},
+		{"before first token", 1, 0, ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ""
+			if n := FindNode(body, tc.line, tc.col); n != nil {
+				got = n.GetToken().Value
+			}
+			if got != tc.want {
+				t.Errorf("FindNode(%d, %d): got %q, want %q", tc.line, tc.col, got, tc.want)
+			}
+		})
+	}
+}
@@ -18,7 +18,7 @@ func main() {
 
 	th := lsp.NewTektonHandler(version)
 
-	server := server.NewServer(&th.Handler, th.Name(), true)
+	server := server.NewServer(th, th.Name(), true)
 	th.Log = server.Log
 
 	server.RunStdio()